@@ -0,0 +1,95 @@
+// Command keygen creates a signing key pair for access tokens and publishes
+// its public half in a JWKS file that the service can load with
+// auth.jwks_source. Running it again with a new -kid adds a key to the set,
+// which is how keys are rotated: publish the new key, switch
+// auth.signing_key_file/signing_key_id to it, and drop the old key from the
+// JWKS once tokens signed with it have expired.
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/malikhisyam/user-graph-service/shared/security"
+)
+
+func main() {
+	dir := flag.String("dir", "./keys", "directory for the private key and jwks.json")
+	kid := flag.String("kid", "dev-1", "key id stamped into token headers")
+	alg := flag.String("alg", "RS256", "RS256 or ES256")
+	flag.Parse()
+
+	var (
+		private crypto.Signer
+		err     error
+	)
+	switch *alg {
+	case "RS256":
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case "ES256":
+		private, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		log.Fatalf("unsupported alg %q", *alg)
+	}
+	if err != nil {
+		log.Fatalf("generate key: %v", err)
+	}
+
+	if err := os.MkdirAll(*dir, 0o700); err != nil {
+		log.Fatalf("create key dir: %v", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		log.Fatalf("encode private key: %v", err)
+	}
+	keyPath := filepath.Join(*dir, *kid+".pem")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		log.Fatalf("write private key: %v", err)
+	}
+
+	jwk, err := security.NewJWK(*kid, private.Public())
+	if err != nil {
+		log.Fatalf("describe public key: %v", err)
+	}
+
+	jwksPath := filepath.Join(*dir, "jwks.json")
+	var set security.JWKS
+	if raw, err := os.ReadFile(jwksPath); err == nil {
+		if err := json.Unmarshal(raw, &set); err != nil {
+			log.Fatalf("read existing jwks: %v", err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		log.Fatalf("read existing jwks: %v", err)
+	}
+
+	keys := set.Keys[:0]
+	for _, k := range set.Keys {
+		if k.Kid != *kid {
+			keys = append(keys, k)
+		}
+	}
+	set.Keys = append(keys, jwk)
+
+	raw, err := json.MarshalIndent(set, "", "  ")
+	if err != nil {
+		log.Fatalf("encode jwks: %v", err)
+	}
+	if err := os.WriteFile(jwksPath, raw, 0o644); err != nil {
+		log.Fatalf("write jwks: %v", err)
+	}
+
+	fmt.Printf("wrote %s and added kid %q to %s\n", keyPath, *kid, jwksPath)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -11,6 +12,13 @@ type (
 	Config struct {
 		Db     *Database
 		Server *Server
+		Stats    *Stats
+		Timeline *Timeline
+		Auth     *Auth
+		Accounts *Accounts
+		Exports  *Exports
+		Idempotency *Idempotency
+		Events      *Events
 	}
 
 	Database struct {
@@ -26,6 +34,74 @@ type (
 	Server struct {
 		Port int
 	}
+
+	Stats struct {
+		ReconcileInterval time.Duration `mapstructure:"reconcile_interval"`
+	}
+
+	Timeline struct {
+		// Authors with at least this many followers are not fanned out on
+		// write; readers pull their posts instead. Zero pushes everyone.
+		FanoutThreshold int64 `mapstructure:"fanout_threshold"`
+	}
+
+	Accounts struct {
+		// Deactivated accounts can be reactivated for this long, after
+		// which the purge job deletes them.
+		ReactivationWindow time.Duration `mapstructure:"reactivation_window"`
+		PurgeInterval      time.Duration `mapstructure:"purge_interval"`
+	}
+
+	Exports struct {
+		StorageDir   string        `mapstructure:"storage_dir"`
+		PollInterval time.Duration `mapstructure:"poll_interval"`
+		// LinkTTL bounds a single download link, Retention the archive.
+		LinkTTL   time.Duration `mapstructure:"link_ttl"`
+		Retention time.Duration
+		// SigningSecret signs download links. Set it through
+		// EXPORTS_SIGNING_SECRET; when empty a random one is used and links
+		// die with the process.
+		SigningSecret string `mapstructure:"signing_secret"`
+	}
+
+	Idempotency struct {
+		// TTL is how long a response is replayed for retries carrying the
+		// same Idempotency-Key.
+		TTL time.Duration
+	}
+
+	Events struct {
+		// Publisher is log, stdout, file or redis.
+		Publisher string
+		FilePath  string `mapstructure:"file_path"`
+		// Stream and StreamMaxLen configure the redis publisher.
+		Stream       string
+		StreamMaxLen int64 `mapstructure:"stream_max_len"`
+		// The relay moves follow events from the outbox table to the
+		// publisher.
+		RelayInterval  time.Duration `mapstructure:"relay_interval"`
+		RelayBatchSize int           `mapstructure:"relay_batch_size"`
+		// RelayParkAfter parks an event still failing that long after its
+		// first failure, until an admin replays it; 0 retries forever.
+		RelayParkAfter time.Duration `mapstructure:"relay_park_after"`
+		// OutboxRetention is how long published events are kept; 0 keeps
+		// them forever.
+		OutboxRetention time.Duration `mapstructure:"outbox_retention"`
+	}
+
+	Auth struct {
+		AccessTokenTTL  time.Duration `mapstructure:"access_token_ttl"`
+		RefreshTokenTTL time.Duration `mapstructure:"refresh_token_ttl"`
+		Issuer         string
+		Audience       string
+		// JwksSource is a local file path or an http(s) URL.
+		JwksSource          string        `mapstructure:"jwks_source"`
+		JwksRefreshInterval time.Duration `mapstructure:"jwks_refresh_interval"`
+		// SigningKeyFile is optional; leave it empty when another system
+		// issues the tokens.
+		SigningKeyFile string `mapstructure:"signing_key_file"`
+		SigningKeyId   string `mapstructure:"signing_key_id"`
+	}
 )
 
 var (
@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/users/entities"
+)
+
+const (
+	ExportStatusPending    = "pending"
+	ExportStatusProcessing = "processing"
+	ExportStatusReady      = "ready"
+	ExportStatusFailed     = "failed"
+	ExportStatusExpired    = "expired"
+)
+
+// DataExports tracks one personal data export from request to download.
+// The archive lives on local storage at FilePath until ExpiresAt.
+type DataExports struct {
+	ID uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey;column:id"`
+
+	UserID uuid.UUID `gorm:"type:uuid;not null;index:idx_data_exports_user_id;column:user_id"`
+	Status string    `gorm:"type:varchar(16);not null;index:idx_data_exports_status;column:status"`
+
+	FilePath    string     `gorm:"type:varchar(512);column:file_path"`
+	Error       string     `gorm:"type:varchar(512);column:error"`
+	StartedAt   *time.Time `gorm:"type:timestamp;column:started_at"`
+	CompletedAt *time.Time `gorm:"type:timestamp;column:completed_at"`
+	ExpiresAt   *time.Time `gorm:"type:timestamp;column:expires_at"`
+
+	User entities.User `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE;"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;column:created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp;column:updated_at"`
+}
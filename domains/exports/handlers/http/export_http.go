@@ -0,0 +1,133 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/exports/entities"
+	"github.com/malikhisyam/user-graph-service/domains/exports/models/responses"
+	"github.com/malikhisyam/user-graph-service/domains/exports/repositories"
+	"github.com/malikhisyam/user-graph-service/domains/exports/usecases"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+)
+
+type ExportHttp struct {
+	exportUc usecases.ExportUseCase
+}
+
+func NewExportHttp(exportUc usecases.ExportUseCase) *ExportHttp {
+	return &ExportHttp{
+		exportUc: exportUc,
+	}
+}
+
+// RequestExport queues an export of the caller's data; the archive is built
+// in the background and polled for through GetExport.
+func (h *ExportHttp) RequestExport(c *gin.Context) {
+	userID, ok := authUserID(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.exportUc.RequestExport(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, h.toExportResponse(export))
+}
+
+func (h *ExportHttp) GetExport(c *gin.Context) {
+	userID, ok := authUserID(c)
+	if !ok {
+		return
+	}
+
+	exportID, err := uuid.Parse(c.Param("exportId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export id"})
+		return
+	}
+
+	export, err := h.exportUc.GetExport(c.Request.Context(), userID, exportID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrExportNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.toExportResponse(export))
+}
+
+// Download serves the archive behind a signed link. It needs no token so
+// the link can be opened straight from a browser or an email.
+func (h *ExportHttp) Download(c *gin.Context) {
+	exportID, err := uuid.Parse(c.Param("exportId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export id"})
+		return
+	}
+
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": usecases.ErrInvalidDownloadLink.Error()})
+		return
+	}
+
+	path, err := h.exportUc.OpenDownload(c.Request.Context(), exportID, expires, c.Query("signature"))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrInvalidDownloadLink):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, repositories.ErrExportNotFound), errors.Is(err, usecases.ErrExportNotReady):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.FileAttachment(path, "user-data-export-"+exportID.String()+".zip")
+}
+
+func (h *ExportHttp) toExportResponse(export *entities.DataExports) responses.ExportResponse {
+	res := responses.ExportResponse{
+		ID:          export.ID.String(),
+		Status:      export.Status,
+		Error:       export.Error,
+		ExpiresAt:   export.ExpiresAt,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+	}
+
+	if export.Status == entities.ExportStatusReady {
+		link, expires := h.exportUc.DownloadLink(export)
+		res.DownloadURL = link
+		res.LinkExpires = &expires
+	}
+
+	return res
+}
+
+func authUserID(c *gin.Context) (uuid.UUID, bool) {
+	authUser, err := util.GetAuthUser(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(authUser.UserId)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user in token"})
+		return uuid.Nil, false
+	}
+
+	return userID, true
+}
@@ -0,0 +1,62 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ExportResponse struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	LinkExpires *time.Time `json:"link_expires_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// The row types below are what the archive holds, one file per dataset in
+// both JSON and CSV.
+
+type ExportConnectionRow struct {
+	UserID   string    `json:"user_id"`
+	Username string    `json:"username"`
+	Name     string    `json:"name"`
+	Since    time.Time `json:"since"`
+	CursorID uuid.UUID `json:"-"`
+}
+
+type ExportFollowHistoryRow struct {
+	FollowerID   string     `json:"follower_id"`
+	FollowingID  string     `json:"following_id"`
+	FollowedAt   time.Time  `json:"followed_at"`
+	UnfollowedAt *time.Time `json:"unfollowed_at"`
+	CursorID     uuid.UUID  `json:"-"`
+}
+
+type ExportFollowRequestRow struct {
+	Direction   string    `json:"direction"`
+	UserID      string    `json:"user_id"`
+	Username    string    `json:"username"`
+	RequestedAt time.Time `json:"requested_at"`
+	CursorID    uuid.UUID `json:"-"`
+}
+
+type ExportProfile struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Username      string     `json:"username"`
+	Email         string     `json:"email"`
+	Bio           string     `json:"bio"`
+	Gender        string     `json:"gender"`
+	Phone         string     `json:"phone"`
+	Country       string     `json:"country"`
+	Profile       string     `json:"profile"`
+	IsPrivate     bool       `json:"is_private"`
+	Role          string     `json:"role"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	DeactivatedAt *time.Time `json:"deactivated_at"`
+}
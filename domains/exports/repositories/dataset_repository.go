@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/exports/models/responses"
+	"go.uber.org/zap"
+)
+
+// The dataset queries page by the row id rather than by time: an export
+// needs every row exactly once, not a particular order, and the primary key
+// is the cheapest stable keyset. uuid.Nil starts from the beginning.
+// Unlike the public listings nothing is hidden here, blocked or deactivated
+// accounts included, since the user is entitled to the full record.
+
+func (r *exportRepository) ListFollowers(ctx context.Context, userID uuid.UUID, after uuid.UUID, limit int) ([]responses.ExportConnectionRow, error) {
+	return r.listConnections(ctx, "followers", `
+		SELECT f.id AS cursor_id, u.id AS user_id, u.username, u.name, f.created_at AS since
+		FROM follows f
+		JOIN users u ON u.id = f.follower_id
+		WHERE f.following_id = @user AND f.deleted_at IS NULL AND f.id > @after
+		ORDER BY f.id
+		LIMIT @limit`, userID, after, limit)
+}
+
+func (r *exportRepository) ListFollowings(ctx context.Context, userID uuid.UUID, after uuid.UUID, limit int) ([]responses.ExportConnectionRow, error) {
+	return r.listConnections(ctx, "followings", `
+		SELECT f.id AS cursor_id, u.id AS user_id, u.username, u.name, f.created_at AS since
+		FROM follows f
+		JOIN users u ON u.id = f.following_id
+		WHERE f.follower_id = @user AND f.deleted_at IS NULL AND f.id > @after
+		ORDER BY f.id
+		LIMIT @limit`, userID, after, limit)
+}
+
+func (r *exportRepository) ListBlocks(ctx context.Context, userID uuid.UUID, after uuid.UUID, limit int) ([]responses.ExportConnectionRow, error) {
+	return r.listConnections(ctx, "blocks", `
+		SELECT b.id AS cursor_id, u.id AS user_id, u.username, u.name, b.created_at AS since
+		FROM blocks b
+		JOIN users u ON u.id = b.blocked_id
+		WHERE b.blocker_id = @user AND b.id > @after
+		ORDER BY b.id
+		LIMIT @limit`, userID, after, limit)
+}
+
+func (r *exportRepository) ListFollowHistory(ctx context.Context, userID uuid.UUID, after uuid.UUID, limit int) ([]responses.ExportFollowHistoryRow, error) {
+	var rows []responses.ExportFollowHistoryRow
+
+	err := r.db.GetInstance().WithContext(ctx).Raw(`
+		SELECT id AS cursor_id, follower_id, following_id, created_at AS followed_at, deleted_at AS unfollowed_at
+		FROM follows
+		WHERE (follower_id = @user OR following_id = @user) AND id > @after
+		ORDER BY id
+		LIMIT @limit`,
+		map[string]interface{}{"user": userID, "after": after, "limit": limit},
+	).Scan(&rows).Error
+	if err != nil {
+		r.logger.Error("Failed to export follow history",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+		)
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *exportRepository) ListFollowRequests(ctx context.Context, userID uuid.UUID, after uuid.UUID, limit int) ([]responses.ExportFollowRequestRow, error) {
+	var rows []responses.ExportFollowRequestRow
+
+	err := r.db.GetInstance().WithContext(ctx).Raw(`
+		SELECT
+			fr.id AS cursor_id,
+			CASE WHEN fr.target_id = @user THEN 'incoming' ELSE 'outgoing' END AS direction,
+			u.id AS user_id,
+			u.username,
+			fr.created_at AS requested_at
+		FROM follow_requests fr
+		JOIN users u ON u.id = CASE WHEN fr.target_id = @user THEN fr.requester_id ELSE fr.target_id END
+		WHERE (fr.requester_id = @user OR fr.target_id = @user) AND fr.id > @after
+		ORDER BY fr.id
+		LIMIT @limit`,
+		map[string]interface{}{"user": userID, "after": after, "limit": limit},
+	).Scan(&rows).Error
+	if err != nil {
+		r.logger.Error("Failed to export follow requests",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+		)
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *exportRepository) listConnections(ctx context.Context, dataset, query string, userID uuid.UUID, after uuid.UUID, limit int) ([]responses.ExportConnectionRow, error) {
+	var rows []responses.ExportConnectionRow
+
+	err := r.db.GetInstance().WithContext(ctx).Raw(query,
+		map[string]interface{}{"user": userID, "after": after, "limit": limit},
+	).Scan(&rows).Error
+	if err != nil {
+		r.logger.Error("Failed to export connections",
+			zap.Error(err),
+			zap.String("dataset", dataset),
+			zap.String("user_id", userID.String()),
+		)
+		return nil, err
+	}
+
+	return rows, nil
+}
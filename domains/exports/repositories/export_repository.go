@@ -0,0 +1,229 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/exports/entities"
+	"github.com/malikhisyam/user-graph-service/domains/exports/models/responses"
+	"github.com/malikhisyam/user-graph-service/infrastructures"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrExportNotFound = errors.New("export not found")
+)
+
+type ExportRepository interface {
+	Create(ctx context.Context, export *entities.DataExports) error
+	FindByID(ctx context.Context, exportID uuid.UUID) (*entities.DataExports, error)
+	FindInProgressForUser(ctx context.Context, userID uuid.UUID) (*entities.DataExports, error)
+	ClaimNext(ctx context.Context, staleBefore time.Time) (*entities.DataExports, error)
+	MarkReady(ctx context.Context, exportID uuid.UUID, filePath string, expiresAt time.Time) error
+	MarkFailed(ctx context.Context, exportID uuid.UUID, reason string) error
+	FindExpired(ctx context.Context, now time.Time, limit int) ([]entities.DataExports, error)
+	MarkExpired(ctx context.Context, exportID uuid.UUID) error
+	FindByUser(ctx context.Context, userID uuid.UUID) ([]entities.DataExports, error)
+	DeleteByUser(ctx context.Context, userID uuid.UUID) error
+
+	ListFollowers(ctx context.Context, userID uuid.UUID, after uuid.UUID, limit int) ([]responses.ExportConnectionRow, error)
+	ListFollowings(ctx context.Context, userID uuid.UUID, after uuid.UUID, limit int) ([]responses.ExportConnectionRow, error)
+	ListBlocks(ctx context.Context, userID uuid.UUID, after uuid.UUID, limit int) ([]responses.ExportConnectionRow, error)
+	ListFollowHistory(ctx context.Context, userID uuid.UUID, after uuid.UUID, limit int) ([]responses.ExportFollowHistoryRow, error)
+	ListFollowRequests(ctx context.Context, userID uuid.UUID, after uuid.UUID, limit int) ([]responses.ExportFollowRequestRow, error)
+}
+
+type exportRepository struct {
+	db     infrastructures.Database
+	logger util.Logger
+}
+
+func NewExportRepository(db infrastructures.Database, logger util.Logger) ExportRepository {
+	return &exportRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *exportRepository) Create(ctx context.Context, export *entities.DataExports) error {
+	if err := r.db.GetInstance().WithContext(ctx).Create(export).Error; err != nil {
+		r.logger.Error("Failed to create data export",
+			zap.Error(err),
+			zap.String("user_id", export.UserID.String()),
+		)
+		return err
+	}
+
+	r.logger.Info("Data export requested",
+		zap.String("export_id", export.ID.String()),
+		zap.String("user_id", export.UserID.String()),
+	)
+	return nil
+}
+
+func (r *exportRepository) FindByID(ctx context.Context, exportID uuid.UUID) (*entities.DataExports, error) {
+	var export entities.DataExports
+	err := r.db.GetInstance().WithContext(ctx).Where("id = ?", exportID).First(&export).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExportNotFound
+		}
+		r.logger.Error("Failed to load data export",
+			zap.Error(err),
+			zap.String("export_id", exportID.String()),
+		)
+		return nil, err
+	}
+
+	return &export, nil
+}
+
+func (r *exportRepository) FindInProgressForUser(ctx context.Context, userID uuid.UUID) (*entities.DataExports, error) {
+	var export entities.DataExports
+	err := r.db.GetInstance().WithContext(ctx).
+		Where("user_id = ? AND status IN ?", userID, []string{entities.ExportStatusPending, entities.ExportStatusProcessing}).
+		Order("created_at DESC").
+		First(&export).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExportNotFound
+		}
+		r.logger.Error("Failed to load in-progress data export",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+		)
+		return nil, err
+	}
+
+	return &export, nil
+}
+
+// ClaimNext moves the oldest pending export, or one whose worker died while
+// processing it before staleBefore, to processing and returns it. SKIP
+// LOCKED lets several instances claim in parallel without picking the same
+// row. It returns ErrExportNotFound when there is nothing to do.
+//
+// started_at is set from Go rather than NOW(): the columns have no time
+// zone, so it must be written the same way staleBefore is compared.
+func (r *exportRepository) ClaimNext(ctx context.Context, staleBefore time.Time) (*entities.DataExports, error) {
+	var export entities.DataExports
+
+	err := r.db.GetInstance().WithContext(ctx).Raw(`
+		UPDATE data_exports
+		SET status = @processing, started_at = @now, updated_at = @now
+		WHERE id = (
+			SELECT id FROM data_exports
+			WHERE status = @pending OR (status = @processing AND started_at < @stale)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		map[string]interface{}{
+			"pending":    entities.ExportStatusPending,
+			"processing": entities.ExportStatusProcessing,
+			"stale":      staleBefore,
+			"now":        time.Now(),
+		},
+	).Scan(&export).Error
+	if err != nil {
+		r.logger.Error("Failed to claim data export", zap.Error(err))
+		return nil, err
+	}
+	if export.ID == uuid.Nil {
+		return nil, ErrExportNotFound
+	}
+
+	return &export, nil
+}
+
+func (r *exportRepository) MarkReady(ctx context.Context, exportID uuid.UUID, filePath string, expiresAt time.Time) error {
+	return r.update(ctx, exportID, map[string]interface{}{
+		"status":       entities.ExportStatusReady,
+		"file_path":    filePath,
+		"completed_at": time.Now(),
+		"expires_at":   expiresAt,
+	})
+}
+
+func (r *exportRepository) MarkFailed(ctx context.Context, exportID uuid.UUID, reason string) error {
+	return r.update(ctx, exportID, map[string]interface{}{
+		"status":       entities.ExportStatusFailed,
+		"error":        reason,
+		"completed_at": time.Now(),
+	})
+}
+
+func (r *exportRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]entities.DataExports, error) {
+	var exports []entities.DataExports
+	err := r.db.GetInstance().WithContext(ctx).
+		Where("status = ? AND expires_at < ?", entities.ExportStatusReady, now).
+		Limit(limit).
+		Find(&exports).Error
+	if err != nil {
+		r.logger.Error("Failed to load expired data exports", zap.Error(err))
+		return nil, err
+	}
+
+	return exports, nil
+}
+
+func (r *exportRepository) MarkExpired(ctx context.Context, exportID uuid.UUID) error {
+	return r.update(ctx, exportID, map[string]interface{}{
+		"status":    entities.ExportStatusExpired,
+		"file_path": "",
+	})
+}
+
+func (r *exportRepository) FindByUser(ctx context.Context, userID uuid.UUID) ([]entities.DataExports, error) {
+	var exports []entities.DataExports
+	err := r.db.GetInstance().WithContext(ctx).
+		Where("user_id = ?", userID).
+		Find(&exports).Error
+	if err != nil {
+		r.logger.Error("Failed to load data exports of user",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+		)
+		return nil, err
+	}
+
+	return exports, nil
+}
+
+func (r *exportRepository) DeleteByUser(ctx context.Context, userID uuid.UUID) error {
+	err := r.db.GetInstance().WithContext(ctx).
+		Where("user_id = ?", userID).
+		Delete(&entities.DataExports{}).Error
+	if err != nil {
+		r.logger.Error("Failed to delete data exports of user",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (r *exportRepository) update(ctx context.Context, exportID uuid.UUID, fields map[string]interface{}) error {
+	fields["updated_at"] = time.Now()
+
+	err := r.db.GetInstance().WithContext(ctx).
+		Model(&entities.DataExports{}).
+		Where("id = ?", exportID).
+		Updates(fields).Error
+	if err != nil {
+		r.logger.Error("Failed to update data export",
+			zap.Error(err),
+			zap.String("export_id", exportID.String()),
+		)
+		return err
+	}
+
+	return nil
+}
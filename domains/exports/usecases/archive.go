@@ -0,0 +1,234 @@
+package usecases
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/exports/models/responses"
+	userEntities "github.com/malikhisyam/user-graph-service/domains/users/entities"
+)
+
+// archivePageSize is how many rows are read per query while streaming a
+// dataset into the archive.
+const archivePageSize = 1000
+
+// dataset streams one table of the export. Rows are fetched page by page,
+// so a user with millions of followers never sits in memory at once; each
+// dataset is read twice, once per format, because a zip entry must be
+// finished before the next one starts.
+type dataset struct {
+	name   string
+	header []string
+	// page returns the rows after cursor as JSON values and CSV records,
+	// plus the cursor of the last row.
+	page func(ctx context.Context, after uuid.UUID) ([]interface{}, [][]string, uuid.UUID, error)
+}
+
+func writeArchive(ctx context.Context, zw *zip.Writer, user *userEntities.User, datasets []dataset) error {
+	profile := responses.ExportProfile{
+		ID:            user.ID.String(),
+		Name:          user.Name,
+		Username:      user.Username,
+		Email:         user.Email,
+		Bio:           user.Bio,
+		Gender:        user.Gender,
+		Phone:         user.Phone,
+		Country:       user.Country,
+		Profile:       user.Profile,
+		IsPrivate:     user.IsPrivate,
+		Role:          user.Role,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+		DeactivatedAt: user.DeactivatedAt,
+	}
+
+	w, err := zw.Create("profile.json")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(profile); err != nil {
+		return err
+	}
+
+	w, err = zw.Create("profile.csv")
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"id", "name", "username", "email", "bio", "gender", "phone", "country", "profile", "is_private", "role", "created_at", "updated_at", "deactivated_at"})
+	cw.Write([]string{
+		profile.ID, profile.Name, profile.Username, profile.Email, profile.Bio, profile.Gender,
+		profile.Phone, profile.Country, profile.Profile, strconv.FormatBool(profile.IsPrivate), profile.Role,
+		formatTime(profile.CreatedAt), formatTime(profile.UpdatedAt), formatOptionalTime(profile.DeactivatedAt),
+	})
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+
+	for _, ds := range datasets {
+		if err := writeJSONDataset(ctx, zw, ds); err != nil {
+			return err
+		}
+		if err := writeCSVDataset(ctx, zw, ds); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeJSONDataset writes a JSON array one element at a time.
+func writeJSONDataset(ctx context.Context, zw *zip.Writer, ds dataset) error {
+	w, err := zw.Create(ds.name + ".json")
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write([]byte("[")); err != nil {
+		return err
+	}
+
+	first := true
+	after := uuid.Nil
+	for {
+		values, _, last, err := ds.page(ctx, after)
+		if err != nil {
+			return err
+		}
+
+		for _, value := range values {
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			if !first {
+				raw = append([]byte(","), raw...)
+			}
+			first = false
+			if _, err := w.Write(append([]byte("\n  "), raw...)); err != nil {
+				return err
+			}
+		}
+
+		if len(values) < archivePageSize {
+			break
+		}
+		after = last
+	}
+
+	_, err = w.Write([]byte("\n]\n"))
+	return err
+}
+
+func writeCSVDataset(ctx context.Context, zw *zip.Writer, ds dataset) error {
+	w, err := zw.Create(ds.name + ".csv")
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(ds.header); err != nil {
+		return err
+	}
+
+	after := uuid.Nil
+	for {
+		_, records, last, err := ds.page(ctx, after)
+		if err != nil {
+			return err
+		}
+
+		if err := cw.WriteAll(records); err != nil {
+			return err
+		}
+
+		if len(records) < archivePageSize {
+			break
+		}
+		after = last
+	}
+
+	return cw.Error()
+}
+
+func connectionDataset(name string, list func(ctx context.Context, after uuid.UUID, limit int) ([]responses.ExportConnectionRow, error)) dataset {
+	return dataset{
+		name:   name,
+		header: []string{"user_id", "username", "name", "since"},
+		page: func(ctx context.Context, after uuid.UUID) ([]interface{}, [][]string, uuid.UUID, error) {
+			rows, err := list(ctx, after, archivePageSize)
+			if err != nil || len(rows) == 0 {
+				return nil, nil, uuid.Nil, err
+			}
+
+			values := make([]interface{}, len(rows))
+			records := make([][]string, len(rows))
+			for i, row := range rows {
+				values[i] = row
+				records[i] = []string{row.UserID, row.Username, row.Name, formatTime(row.Since)}
+			}
+			return values, records, rows[len(rows)-1].CursorID, nil
+		},
+	}
+}
+
+func followHistoryDataset(list func(ctx context.Context, after uuid.UUID, limit int) ([]responses.ExportFollowHistoryRow, error)) dataset {
+	return dataset{
+		name:   "follow_history",
+		header: []string{"follower_id", "following_id", "followed_at", "unfollowed_at"},
+		page: func(ctx context.Context, after uuid.UUID) ([]interface{}, [][]string, uuid.UUID, error) {
+			rows, err := list(ctx, after, archivePageSize)
+			if err != nil || len(rows) == 0 {
+				return nil, nil, uuid.Nil, err
+			}
+
+			values := make([]interface{}, len(rows))
+			records := make([][]string, len(rows))
+			for i, row := range rows {
+				values[i] = row
+				records[i] = []string{row.FollowerID, row.FollowingID, formatTime(row.FollowedAt), formatOptionalTime(row.UnfollowedAt)}
+			}
+			return values, records, rows[len(rows)-1].CursorID, nil
+		},
+	}
+}
+
+func followRequestDataset(list func(ctx context.Context, after uuid.UUID, limit int) ([]responses.ExportFollowRequestRow, error)) dataset {
+	return dataset{
+		name:   "follow_requests",
+		header: []string{"direction", "user_id", "username", "requested_at"},
+		page: func(ctx context.Context, after uuid.UUID) ([]interface{}, [][]string, uuid.UUID, error) {
+			rows, err := list(ctx, after, archivePageSize)
+			if err != nil || len(rows) == 0 {
+				return nil, nil, uuid.Nil, err
+			}
+
+			values := make([]interface{}, len(rows))
+			records := make([][]string, len(rows))
+			for i, row := range rows {
+				values[i] = row
+				records[i] = []string{row.Direction, row.UserID, row.Username, formatTime(row.RequestedAt)}
+			}
+			return values, records, rows[len(rows)-1].CursorID, nil
+		},
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return formatTime(*t)
+}
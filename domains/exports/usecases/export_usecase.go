@@ -0,0 +1,276 @@
+package usecases
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/exports/entities"
+	"github.com/malikhisyam/user-graph-service/domains/exports/models/responses"
+	"github.com/malikhisyam/user-graph-service/domains/exports/repositories"
+	userRepo "github.com/malikhisyam/user-graph-service/domains/users/repositories"
+	"github.com/malikhisyam/user-graph-service/shared/security"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidDownloadLink = errors.New("download link is invalid or has expired")
+	ErrExportNotReady      = errors.New("export is not ready for download")
+)
+
+// staleAfter is how long an export may sit in processing before another
+// worker assumes the first one died and takes it over.
+const staleAfter = time.Hour
+
+type ExportUseCase interface {
+	RequestExport(ctx context.Context, userID uuid.UUID) (*entities.DataExports, error)
+	GetExport(ctx context.Context, userID, exportID uuid.UUID) (*entities.DataExports, error)
+	DownloadLink(export *entities.DataExports) (string, time.Time)
+	OpenDownload(ctx context.Context, exportID uuid.UUID, expires int64, signature string) (string, error)
+	ProcessNext(ctx context.Context) (bool, error)
+	ExpireArchives(ctx context.Context) (int, error)
+	PurgeUser(ctx context.Context, userID uuid.UUID) error
+}
+
+type exportUsecase struct {
+	exportRepo repositories.ExportRepository
+	userRepo   userRepo.UserRepository
+	signer     *security.URLSigner
+	storageDir string
+	linkTTL    time.Duration
+	retention  time.Duration
+	logger     util.Logger
+}
+
+func NewExportUseCase(
+	exportRepo repositories.ExportRepository,
+	userRepo userRepo.UserRepository,
+	signer *security.URLSigner,
+	storageDir string,
+	linkTTL time.Duration,
+	retention time.Duration,
+	logger util.Logger,
+) ExportUseCase {
+	return &exportUsecase{
+		exportRepo: exportRepo,
+		userRepo:   userRepo,
+		signer:     signer,
+		storageDir: storageDir,
+		linkTTL:    linkTTL,
+		retention:  retention,
+		logger:     logger,
+	}
+}
+
+// RequestExport queues a new export, or returns the one already queued so
+// repeated clicks do not pile up work.
+func (u *exportUsecase) RequestExport(ctx context.Context, userID uuid.UUID) (*entities.DataExports, error) {
+	existing, err := u.exportRepo.FindInProgressForUser(ctx, userID)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, repositories.ErrExportNotFound) {
+		return nil, err
+	}
+
+	export := &entities.DataExports{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Status:    entities.ExportStatusPending,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := u.exportRepo.Create(ctx, export); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// GetExport only returns exports of the given user; anybody else's look
+// missing.
+func (u *exportUsecase) GetExport(ctx context.Context, userID, exportID uuid.UUID) (*entities.DataExports, error) {
+	export, err := u.exportRepo.FindByID(ctx, exportID)
+	if err != nil {
+		return nil, err
+	}
+	if export.UserID != userID {
+		return nil, repositories.ErrExportNotFound
+	}
+
+	return export, nil
+}
+
+// DownloadLink signs a link to the archive valid for the link TTL, never
+// beyond the archive's own expiry.
+func (u *exportUsecase) DownloadLink(export *entities.DataExports) (string, time.Time) {
+	expires := time.Now().Add(u.linkTTL)
+	if export.ExpiresAt != nil && export.ExpiresAt.Before(expires) {
+		expires = *export.ExpiresAt
+	}
+
+	signature := u.signer.Sign(export.ID.String(), expires)
+	link := fmt.Sprintf("/api/v1/exports/%s/download?expires=%d&signature=%s", export.ID, expires.Unix(), signature)
+	return link, expires
+}
+
+// OpenDownload validates a signed link and returns the archive path.
+func (u *exportUsecase) OpenDownload(ctx context.Context, exportID uuid.UUID, expires int64, signature string) (string, error) {
+	if !u.signer.Verify(exportID.String(), expires, signature) {
+		return "", ErrInvalidDownloadLink
+	}
+
+	export, err := u.exportRepo.FindByID(ctx, exportID)
+	if err != nil {
+		return "", err
+	}
+	if export.Status != entities.ExportStatusReady || export.FilePath == "" {
+		return "", ErrExportNotReady
+	}
+
+	return export.FilePath, nil
+}
+
+// ProcessNext builds the archive of the next queued export and reports
+// whether there was one.
+func (u *exportUsecase) ProcessNext(ctx context.Context) (bool, error) {
+	export, err := u.exportRepo.ClaimNext(ctx, time.Now().Add(-staleAfter))
+	if err != nil {
+		if errors.Is(err, repositories.ErrExportNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	started := time.Now()
+	path, err := u.buildArchive(ctx, export)
+	if err != nil {
+		u.logger.Error("Failed to build data export",
+			zap.Error(err),
+			zap.String("export_id", export.ID.String()),
+		)
+		return true, u.exportRepo.MarkFailed(ctx, export.ID, "archive could not be built")
+	}
+
+	if err := u.exportRepo.MarkReady(ctx, export.ID, path, time.Now().Add(u.retention)); err != nil {
+		return true, err
+	}
+
+	u.logger.Info("Data export ready",
+		zap.String("export_id", export.ID.String()),
+		zap.String("user_id", export.UserID.String()),
+		zap.Duration("took", time.Since(started)),
+	)
+	return true, nil
+}
+
+// ExpireArchives deletes archives past their retention.
+func (u *exportUsecase) ExpireArchives(ctx context.Context) (int, error) {
+	expired, err := u.exportRepo.FindExpired(ctx, time.Now(), 100)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, export := range expired {
+		if err := removeArchive(export.FilePath); err != nil {
+			return 0, err
+		}
+		if err := u.exportRepo.MarkExpired(ctx, export.ID); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(expired), nil
+}
+
+// PurgeUser deletes the archives and export records of a deleted user.
+func (u *exportUsecase) PurgeUser(ctx context.Context, userID uuid.UUID) error {
+	exports, err := u.exportRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		if err := removeArchive(export.FilePath); err != nil {
+			return err
+		}
+	}
+
+	return u.exportRepo.DeleteByUser(ctx, userID)
+}
+
+// buildArchive writes the zip to a temporary name first and renames it into
+// place, so a crash never leaves a truncated archive behind a ready export.
+func (u *exportUsecase) buildArchive(ctx context.Context, export *entities.DataExports) (string, error) {
+	user, err := u.userRepo.FindByID(ctx, export.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(u.storageDir, 0o700); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(u.storageDir, export.ID.String()+".zip")
+	tmpPath := path + ".tmp"
+
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmpPath)
+
+	zw := zip.NewWriter(file)
+	if err := writeArchive(ctx, zw, user, u.datasets(export.UserID)); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (u *exportUsecase) datasets(userID uuid.UUID) []dataset {
+	return []dataset{
+		connectionDataset("followers", func(ctx context.Context, after uuid.UUID, limit int) ([]responses.ExportConnectionRow, error) {
+			return u.exportRepo.ListFollowers(ctx, userID, after, limit)
+		}),
+		connectionDataset("followings", func(ctx context.Context, after uuid.UUID, limit int) ([]responses.ExportConnectionRow, error) {
+			return u.exportRepo.ListFollowings(ctx, userID, after, limit)
+		}),
+		connectionDataset("blocks", func(ctx context.Context, after uuid.UUID, limit int) ([]responses.ExportConnectionRow, error) {
+			return u.exportRepo.ListBlocks(ctx, userID, after, limit)
+		}),
+		followHistoryDataset(func(ctx context.Context, after uuid.UUID, limit int) ([]responses.ExportFollowHistoryRow, error) {
+			return u.exportRepo.ListFollowHistory(ctx, userID, after, limit)
+		}),
+		followRequestDataset(func(ctx context.Context, after uuid.UUID, limit int) ([]responses.ExportFollowRequestRow, error) {
+			return u.exportRepo.ListFollowRequests(ctx, userID, after, limit)
+		}),
+	}
+}
+
+func removeArchive(path string) error {
+	if path == "" {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/malikhisyam/user-graph-service/domains/exports/usecases"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+	"go.uber.org/zap"
+)
+
+// ExportWorker builds queued data export archives and deletes the ones past
+// their retention.
+type ExportWorker struct {
+	exportUc usecases.ExportUseCase
+	interval time.Duration
+	logger   util.Logger
+}
+
+func NewExportWorker(exportUc usecases.ExportUseCase, interval time.Duration, logger util.Logger) *ExportWorker {
+	return &ExportWorker{
+		exportUc: exportUc,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (w *ExportWorker) Run(ctx context.Context) {
+	if w.interval <= 0 {
+		w.logger.Warn("Data exports disabled, no poll interval configured")
+		return
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.drain(ctx)
+
+			expired, err := w.exportUc.ExpireArchives(ctx)
+			if err != nil {
+				w.logger.Error("Failed to expire data export archives", zap.Error(err))
+			} else if expired > 0 {
+				w.logger.Info("Expired data export archives", zap.Int("archives", expired))
+			}
+		}
+	}
+}
+
+// drain works through the queue until it is empty or an error occurs.
+func (w *ExportWorker) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := w.exportUc.ProcessNext(ctx)
+		if err != nil {
+			w.logger.Error("Data export processing failed", zap.Error(err))
+			return
+		}
+		if !processed {
+			return
+		}
+	}
+}
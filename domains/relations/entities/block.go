@@ -0,0 +1,21 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/users/entities"
+)
+
+type Blocks struct {
+	ID uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey;column:id"`
+
+	BlockerID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_blocks_blocker_blocked,priority:1;column:blocker_id"`
+	BlockedID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_blocks_blocker_blocked,priority:2;index:idx_blocks_blocked_id;column:blocked_id"`
+
+	Blocker entities.User `gorm:"foreignKey:BlockerID;references:ID;constraint:OnDelete:CASCADE;"`
+	Blocked entities.User `gorm:"foreignKey:BlockedID;references:ID;constraint:OnDelete:CASCADE;"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;column:created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp;column:updated_at"`
+}
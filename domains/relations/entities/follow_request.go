@@ -0,0 +1,23 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/users/entities"
+)
+
+// FollowRequests holds pending follows towards private accounts until the
+// target approves, rejects, or the requester cancels them.
+type FollowRequests struct {
+	ID uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey;column:id"`
+
+	RequesterID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_follow_requests_requester_target,priority:1;column:requester_id"`
+	TargetID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_follow_requests_requester_target,priority:2;index:idx_follow_requests_target_id;column:target_id"`
+
+	Requester entities.User `gorm:"foreignKey:RequesterID;references:ID;constraint:OnDelete:CASCADE;"`
+	Target    entities.User `gorm:"foreignKey:TargetID;references:ID;constraint:OnDelete:CASCADE;"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;column:created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp;column:updated_at"`
+}
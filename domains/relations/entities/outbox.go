@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Outbox holds follow events written in the same transaction as the edge
+// change they describe. The relay publishes pending rows in Seq order and
+// stamps PublishedAt; a row may be published more than once, never lost.
+// A failed row is retried at NextAttemptAt; one failing for too long is
+// stamped ParkedAt and, with its follower's later rows, waits for an
+// operator to replay it.
+type Outbox struct {
+	Seq int64     `gorm:"primaryKey;autoIncrement;index:idx_outbox_pending,where:published_at IS NULL AND parked_at IS NULL;index:idx_outbox_partition_pending,priority:2,where:published_at IS NULL;column:seq"`
+	ID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_outbox_id;column:id"`
+
+	Type string `gorm:"type:varchar(64);not null;column:type"`
+	// PartitionKey is the follower; events sharing it are published in order.
+	PartitionKey uuid.UUID `gorm:"type:uuid;not null;index:idx_outbox_partition_pending,priority:1;column:partition_key"`
+	Payload      string    `gorm:"type:jsonb;not null;column:payload"`
+	Attempts     int       `gorm:"not null;default:0;column:attempts"`
+
+	NextAttemptAt *time.Time `gorm:"type:timestamp;column:next_attempt_at"`
+	FailingSince  *time.Time `gorm:"type:timestamp;column:failing_since"`
+
+	CreatedAt   time.Time  `gorm:"type:timestamp;column:created_at"`
+	PublishedAt *time.Time `gorm:"type:timestamp;index:idx_outbox_published_at;column:published_at"`
+	ParkedAt    *time.Time `gorm:"type:timestamp;column:parked_at"`
+}
+
+func (Outbox) TableName() string {
+	return "outbox"
+}
@@ -5,11 +5,16 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/malikhisyam/user-graph-service/domains/users/entities"
+	"gorm.io/gorm"
 )
 
 type Follows struct {
 	ID uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey;column:id"`
 
+	// Only one live edge may exist per pair; soft-deleted rows are kept for
+	// the audit trail and are excluded from the unique index. That index,
+	// idx_follows_active_pair, is built by repositories.ActivePairStatements
+	// rather than here, because existing duplicates must go first.
 	FollowerID  uuid.UUID `gorm:"type:uuid;not null;index:idx_follows_follower_id;column:follower_id"`
 	FollowingID uuid.UUID `gorm:"type:uuid;not null;index:idx_follows_following_id;column:following_id"`
 
@@ -18,6 +23,7 @@ type Follows struct {
 
 	CreatedAt time.Time      `gorm:"type:timestamp;column:created_at"`
 	UpdatedAt time.Time      `gorm:"type:timestamp;column:updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"type:timestamp;index;column:deleted_at"`
 }
 
 
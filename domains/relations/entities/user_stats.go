@@ -0,0 +1,25 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/users/entities"
+)
+
+// UserStats keeps denormalised follower/following totals per user. Rows are
+// adjusted in the same transaction as the follow edge they describe and
+// periodically reconciled against the follows table.
+type UserStats struct {
+	UserID uuid.UUID     `gorm:"type:uuid;primaryKey;column:user_id"`
+	User   entities.User `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE;"`
+
+	FollowersCount  int64 `gorm:"type:bigint;not null;default:0;column:followers_count"`
+	FollowingsCount int64 `gorm:"type:bigint;not null;default:0;column:followings_count"`
+
+	UpdatedAt time.Time `gorm:"type:timestamp;column:updated_at"`
+}
+
+func (UserStats) TableName() string {
+	return "user_stats"
+}
@@ -0,0 +1,147 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/relations/entities"
+	"github.com/malikhisyam/user-graph-service/domains/relations/models/responses"
+	"github.com/malikhisyam/user-graph-service/shared/constant"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+)
+
+func (h *RelationHttp) GetIncomingFollowRequests(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.Error(errInvalidUserID)
+		return
+	}
+
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	requests, err := h.relationUc.GetIncomingFollowRequests(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toFollowRequestsResponse(requests))
+}
+
+func (h *RelationHttp) GetOutgoingFollowRequests(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.Error(errInvalidUserID)
+		return
+	}
+
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	requests, err := h.relationUc.GetOutgoingFollowRequests(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toFollowRequestsResponse(requests))
+}
+
+func (h *RelationHttp) ApproveFollowRequest(c *gin.Context) {
+	requestID, err := uuid.Parse(c.Param("requestId"))
+	if err != nil {
+		c.Error(errInvalidRequestID)
+		return
+	}
+
+	if !h.authorizeFollowRequest(c, requestID, func(r *entities.FollowRequests) uuid.UUID { return r.TargetID }) {
+		return
+	}
+
+	if err := h.relationUc.ApproveFollowRequest(c.Request.Context(), requestID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "follow request approved"})
+}
+
+func (h *RelationHttp) RejectFollowRequest(c *gin.Context) {
+	requestID, err := uuid.Parse(c.Param("requestId"))
+	if err != nil {
+		c.Error(errInvalidRequestID)
+		return
+	}
+
+	if !h.authorizeFollowRequest(c, requestID, func(r *entities.FollowRequests) uuid.UUID { return r.TargetID }) {
+		return
+	}
+
+	if err := h.relationUc.RejectFollowRequest(c.Request.Context(), requestID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "follow request rejected"})
+}
+
+func (h *RelationHttp) CancelFollowRequest(c *gin.Context) {
+	requestID, err := uuid.Parse(c.Param("requestId"))
+	if err != nil {
+		c.Error(errInvalidRequestID)
+		return
+	}
+
+	if !h.authorizeFollowRequest(c, requestID, func(r *entities.FollowRequests) uuid.UUID { return r.RequesterID }) {
+		return
+	}
+
+	if err := h.relationUc.CancelFollowRequest(c.Request.Context(), requestID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "follow request cancelled"})
+}
+
+// authorizeFollowRequest loads the request and checks the caller owns the
+// side of it returned by owner: the target approves or rejects, the
+// requester cancels.
+func (h *RelationHttp) authorizeFollowRequest(c *gin.Context, requestID uuid.UUID, owner func(*entities.FollowRequests) uuid.UUID) bool {
+	request, err := h.relationUc.GetFollowRequest(c.Request.Context(), requestID)
+	if err != nil {
+		c.Error(err)
+		return false
+	}
+
+	if err := util.AuthorizeScopedActor(c.Request.Context(), owner(request), constant.SCOPE_RELATIONS_WRITE); err != nil {
+		c.Error(err)
+		return false
+	}
+
+	return true
+}
+
+func toFollowRequestsResponse(requests []responses.FollowRequestWithUserInfo) responses.GetFollowRequestsResponse {
+	requestResponses := make([]responses.FollowRequestResponse, 0, len(requests))
+	for _, r := range requests {
+		requestResponses = append(requestResponses, responses.FollowRequestResponse{
+			ID:          r.ID,
+			RequesterID: r.RequesterID,
+			TargetID:    r.TargetID,
+			Name:        r.Name,
+			Username:    r.Username,
+			CreatedAt:   r.CreatedAt,
+		})
+	}
+
+	return responses.GetFollowRequestsResponse{
+		Requests: requestResponses,
+	}
+}
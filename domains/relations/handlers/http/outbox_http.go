@@ -0,0 +1,73 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/relations/models/responses"
+	"github.com/malikhisyam/user-graph-service/shared/apperror"
+)
+
+var errInvalidEventID = apperror.Validation("invalid_event_id", "Invalid event id")
+
+// ListParkedEvents shows the outbox events the relay gave up on, oldest
+// first, so an operator can decide when to replay them.
+func (h *RelationHttp) ListParkedEvents(c *gin.Context) {
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	parked, err := h.relationUc.ListParkedOutbox(c.Request.Context(), limit, offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	events := make([]responses.ParkedEventResponse, 0, len(parked))
+	for _, row := range parked {
+		events = append(events, responses.ParkedEventResponse{
+			ID:           row.ID.String(),
+			Seq:          row.Seq,
+			Type:         row.Type,
+			PartitionKey: row.PartitionKey.String(),
+			Payload:      json.RawMessage(row.Payload),
+			Attempts:     row.Attempts,
+			CreatedAt:    row.CreatedAt,
+			FailingSince: row.FailingSince,
+			ParkedAt:     row.ParkedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, responses.GetParkedEventsResponse{Events: events})
+}
+
+// ReplayParkedEvents hands every parked event back to the relay.
+func (h *RelationHttp) ReplayParkedEvents(c *gin.Context) {
+	replayed, err := h.relationUc.ReplayParkedOutbox(c.Request.Context(), uuid.Nil)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "parked events replayed", "replayed": replayed})
+}
+
+// ReplayParkedEvent hands one parked event back to the relay.
+func (h *RelationHttp) ReplayParkedEvent(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("eventId"))
+	if err != nil {
+		c.Error(errInvalidEventID)
+		return
+	}
+
+	replayed, err := h.relationUc.ReplayParkedOutbox(c.Request.Context(), eventID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "parked event replayed", "replayed": replayed})
+}
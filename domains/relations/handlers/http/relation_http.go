@@ -1,13 +1,29 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/malikhisyam/user-graph-service/domains/relations/models/requests"
 	"github.com/malikhisyam/user-graph-service/domains/relations/models/responses"
+	"github.com/malikhisyam/user-graph-service/domains/relations/repositories"
 	"github.com/malikhisyam/user-graph-service/domains/relations/usecases"
+	"github.com/malikhisyam/user-graph-service/shared/apperror"
+	"github.com/malikhisyam/user-graph-service/shared/constant"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+)
+
+var (
+	errInvalidPage       = apperror.Validation("invalid_page", "Invalid page parameter")
+	errInvalidLimit      = apperror.Validation("invalid_limit", "Invalid limit parameter")
+	errInvalidUserID     = apperror.Validation("invalid_user_id", "Invalid user id")
+	errInvalidFollowerID = apperror.Validation("invalid_follower_id", "Invalid follower id")
+	errInvalidRequestID  = apperror.Validation("invalid_request_id", "Invalid request id")
+	errNoBulkTargets     = apperror.Validation("missing_targets", "target_ids or usernames is required")
+	errInvalidIdempotent = apperror.Validation("invalid_idempotent", "Invalid idempotent parameter")
 )
 
 type RelationHttp struct {
@@ -20,48 +36,150 @@ func NewRelationHttp(relationUc usecases.RelationUseCase) *RelationHttp {
 	}
 }
 
+// Follow fails with 409 when the edge or request already exists, unless
+// idempotent=true is set, in which case a repeat answers like the first
+// call with created:false.
 func (h *RelationHttp) Follow(c *gin.Context) {
 	var req requests.FollowRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperror.Validation(apperror.CodeInvalidRequest, err.Error()))
+		return
+	}
+
+	idempotent, err := strconv.ParseBool(c.DefaultQuery("idempotent", "false"))
+	if err != nil {
+		c.Error(errInvalidIdempotent)
+		return
+	}
+
+	actorID, err := util.ResolveActor(c.Request.Context(), req.FollowerID, constant.SCOPE_RELATIONS_WRITE)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	created := true
+	status, err := h.relationUc.Follow(c.Request.Context(), actorID, req.FollowingID)
+	if err != nil {
+		switch {
+		case idempotent && errors.Is(err, repositories.ErrAlreadyFollowing):
+			status, created = usecases.FollowStatusFollowed, false
+		case idempotent && errors.Is(err, repositories.ErrFollowRequestExists):
+			status, created = usecases.FollowStatusRequested, false
+		default:
+			c.Error(err)
+			return
+		}
+	}
+
+	if status == usecases.FollowStatusRequested {
+		c.JSON(http.StatusAccepted, gin.H{"message": "follow request sent", "status": status, "created": created})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "follow success", "status": status, "created": created})
+}
+
+// maxBulkFollowTargets caps ids plus usernames in one bulk follow.
+const maxBulkFollowTargets = 200
+
+func (h *RelationHttp) BulkFollow(c *gin.Context) {
+	var req requests.BulkFollowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Validation(apperror.CodeInvalidRequest, err.Error()))
+		return
+	}
+
+	total := len(req.TargetIDs) + len(req.Usernames)
+	if total == 0 {
+		c.Error(errNoBulkTargets)
+		return
+	}
+	if total > maxBulkFollowTargets {
+		c.Error(apperror.Validation("too_many_targets", "at most "+strconv.Itoa(maxBulkFollowTargets)+" targets are allowed"))
 		return
 	}
 
-	err := h.relationUc.Follow(c.Request.Context(), req.FollowerID, req.FollowingID)
+	actorID, err := util.ResolveActor(c.Request.Context(), req.FollowerID, constant.SCOPE_RELATIONS_WRITE)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "follow success"})
+	results, err := h.relationUc.BulkFollow(c.Request.Context(), actorID, req.TargetIDs, req.Usernames)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "bulk follow processed", "results": results})
 }
 
 func (h *RelationHttp) Unfollow(c *gin.Context) {
 	var req requests.UnfollowRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperror.Validation(apperror.CodeInvalidRequest, err.Error()))
 		return
 	}
 
-	err := h.relationUc.Unfollow(c.Request.Context(), req.FollowerID, req.FollowingID)
+	actorID, err := util.ResolveActor(c.Request.Context(), req.FollowerID, constant.SCOPE_RELATIONS_WRITE)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
+		return
+	}
+
+	err = h.relationUc.Unfollow(c.Request.Context(), actorID, req.FollowingID)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "unfollow success"})
 }
 
+func (h *RelationHttp) RemoveFollower(c *gin.Context) {
+	followerID, err := uuid.Parse(c.Param("followerId"))
+	if err != nil {
+		c.Error(errInvalidFollowerID)
+		return
+	}
+
+	// user_id names the account losing the follower; only admin/service
+	// callers and API keys may set it.
+	var onBehalfOf uuid.UUID
+	if raw := c.Query("user_id"); raw != "" {
+		onBehalfOf, err = uuid.Parse(raw)
+		if err != nil {
+			c.Error(errInvalidUserID)
+			return
+		}
+	}
+
+	actorID, err := util.ResolveActor(c.Request.Context(), onBehalfOf, constant.SCOPE_RELATIONS_WRITE)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = h.relationUc.RemoveFollower(c.Request.Context(), actorID, followerID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "follower removed"})
+}
+
 func (h *RelationHttp) IsFollowing(c *gin.Context) {
 	var req requests.IsFollowingRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperror.Validation(apperror.CodeInvalidRequest, err.Error()))
 		return
 	}
 
 	isFollowing, err := h.relationUc.IsFollowing(c.Request.Context(), req.FollowerID, req.FollowingID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -72,7 +190,36 @@ func (h *RelationHttp) IsFollowing(c *gin.Context) {
 }
 
 func (h *RelationHttp) GetFollowers(c *gin.Context) {
-	userId := c.Param("userId")
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.Error(errInvalidUserID)
+		return
+	}
+	nameFilter := c.DefaultQuery("name", "")
+
+	// Cursor mode, opted into by sending the cursor parameter (empty for the first page)
+	if rawCursor, ok := c.GetQuery("cursor"); ok {
+		limit, after, ok := parseCursorPagination(c, rawCursor)
+		if !ok {
+			return
+		}
+
+		followers, next, err := h.relationUc.GetFollowersByCursor(c.Request.Context(), userID, limit, after, nameFilter)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		resp := responses.GetFollowersResponse{
+			Followers: toFollowerResponses(followers),
+		}
+		if next != nil {
+			resp.NextCursor = util.EncodeCursor(*next)
+		}
+
+		c.JSON(http.StatusOK, resp)
+		return
+	}
 
 	// Pagination
 	pageStr := c.DefaultQuery("page", "1")
@@ -80,47 +227,64 @@ func (h *RelationHttp) GetFollowers(c *gin.Context) {
 
 	page, err := strconv.Atoi(pageStr)
 	if err != nil || page < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page parameter"})
+		c.Error(errInvalidPage)
 		return
 	}
 
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		c.Error(errInvalidLimit)
 		return
 	}
 
 	offset := (page - 1) * limit
 
-	nameFilter := c.DefaultQuery("name", "")
-
-	followers, err := h.relationUc.GetFollowers(c.Request.Context(), userId, limit, offset, nameFilter)
+	followers, err := h.relationUc.GetFollowers(c.Request.Context(), userID, limit, offset, nameFilter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
-	var followerResponses []responses.FollowerResponse
-	for _, f := range followers {
-		followerResponses = append(followerResponses, responses.FollowerResponse{
-			ID:          f.ID,
-			FollowerID:  f.FollowerID,
-			DisplayName: f.Name,
-			Username:    f.Username,
-		})
-	}
-
 	resp := responses.GetFollowersResponse{
-		Followers: followerResponses,
+		Followers: toFollowerResponses(followers),
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
 
+func (h *RelationHttp) GetFollowings(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.Error(errInvalidUserID)
+		return
+	}
 
+	// Name filter
+	nameFilter := c.DefaultQuery("name", "")
 
-func (h *RelationHttp) GetFollowings(c *gin.Context) {
-	userId := c.Param("userId")
+	// Cursor mode, opted into by sending the cursor parameter (empty for the first page)
+	if rawCursor, ok := c.GetQuery("cursor"); ok {
+		limit, after, ok := parseCursorPagination(c, rawCursor)
+		if !ok {
+			return
+		}
+
+		followings, next, err := h.relationUc.GetFollowingsByCursor(c.Request.Context(), userID, limit, after, nameFilter)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		resp := responses.GetFollowingsResponse{
+			Followings: toFollowingResponses(followings),
+		}
+		if next != nil {
+			resp.NextCursor = util.EncodeCursor(*next)
+		}
+
+		c.JSON(http.StatusOK, resp)
+		return
+	}
 
 	// Pagination
 	pageStr := c.DefaultQuery("page", "1")
@@ -128,45 +292,277 @@ func (h *RelationHttp) GetFollowings(c *gin.Context) {
 
 	page, err := strconv.Atoi(pageStr)
 	if err != nil || page < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page parameter"})
+		c.Error(errInvalidPage)
 		return
 	}
 
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		c.Error(errInvalidLimit)
 		return
 	}
 
 	offset := (page - 1) * limit
 
-	// Name filter
-	nameFilter := c.DefaultQuery("name", "")
-
 	// Usecase
-	followings, err := h.relationUc.GetFollowings(c.Request.Context(), userId, limit, offset, nameFilter)
+	followings, err := h.relationUc.GetFollowings(c.Request.Context(), userID, limit, offset, nameFilter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
+	resp := responses.GetFollowingsResponse{
+		Followings: toFollowingResponses(followings),
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func toFollowerResponses(followers []responses.FollowerWithUserInfo) []responses.FollowerResponse {
+	var followerResponses []responses.FollowerResponse
+	for _, f := range followers {
+		followerResponses = append(followerResponses, responses.FollowerResponse{
+			ID:          f.ID,
+			FollowerID:  f.FollowerID,
+			DisplayName: f.Name,
+			Username:    f.Username,
+			CreatedAt:   f.CreatedAt,
+		})
+	}
+	return followerResponses
+}
+
+func toFollowingResponses(followings []responses.FollowingWithUserInfo) []responses.FollowingResponse {
 	var followingResponses []responses.FollowingResponse
 	for _, f := range followings {
 		followingResponses = append(followingResponses, responses.FollowingResponse{
-			ID:           f.ID,
-			FollowerID:   f.FollowerID,
-			FollowingID:  f.FollowingID,
-			Name:  f.Name,
-			Username:     f.Username,
-			CreatedAt:    f.CreatedAt,
+			ID:          f.ID,
+			FollowerID:  f.FollowerID,
+			FollowingID: f.FollowingID,
+			Name:        f.Name,
+			Username:    f.Username,
+			CreatedAt:   f.CreatedAt,
 		})
 	}
+	return followingResponses
+}
 
-	resp := responses.GetFollowingsResponse{
-		Followings: followingResponses,
+func (h *RelationHttp) GetMutuals(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.Error(errInvalidUserID)
+		return
 	}
 
-	c.JSON(http.StatusOK, resp)
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	nameFilter := c.DefaultQuery("name", "")
+
+	mutuals, err := h.relationUc.GetMutuals(c.Request.Context(), userID, limit, offset, nameFilter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	mutualResponses := make([]responses.MutualResponse, 0, len(mutuals))
+	for _, m := range mutuals {
+		mutualResponses = append(mutualResponses, responses.MutualResponse{
+			ID:          m.ID,
+			UserID:      m.FollowerID,
+			DisplayName: m.Name,
+			Username:    m.Username,
+		})
+	}
+
+	c.JSON(http.StatusOK, responses.GetMutualsResponse{
+		Mutuals: mutualResponses,
+	})
+}
+
+func (h *RelationHttp) GetSuggestions(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.Error(errInvalidUserID)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 50 {
+		c.Error(errInvalidLimit)
+		return
+	}
+
+	suggestions, err := h.relationUc.GetSuggestions(c.Request.Context(), userID, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	suggestionResponses := make([]responses.SuggestionResponse, 0, len(suggestions))
+	for _, s := range suggestions {
+		followedBy := s.FollowedBy
+		if followedBy == nil {
+			followedBy = []string{}
+		}
+		suggestionResponses = append(suggestionResponses, responses.SuggestionResponse{
+			UserID:     s.FollowingID,
+			Name:       s.Name,
+			Username:   s.Username,
+			AvatarURL:  s.AvatarURL,
+			Score:      s.Score,
+			FollowedBy: followedBy,
+		})
+	}
+
+	c.JSON(http.StatusOK, responses.GetSuggestionsResponse{
+		Suggestions: suggestionResponses,
+	})
+}
+
+func (h *RelationHttp) GetStats(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.Error(errInvalidUserID)
+		return
+	}
+
+	stats, err := h.relationUc.GetStats(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.StatsResponse{
+		UserID:          userID.String(),
+		FollowersCount:  stats.FollowersCount,
+		FollowingsCount: stats.FollowingsCount,
+	})
 }
 
+func (h *RelationHttp) GetHistory(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.Error(errInvalidUserID)
+		return
+	}
+
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	events, err := h.relationUc.GetHistory(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
 
+	eventResponses := make([]responses.FollowEventResponse, 0, len(events))
+	for _, e := range events {
+		eventResponses = append(eventResponses, responses.FollowEventResponse{
+			FollowID:          e.FollowID,
+			Event:             e.Event,
+			FollowerID:        e.FollowerID,
+			FollowingID:       e.FollowingID,
+			FollowerUsername:  e.FollowerUsername,
+			FollowingUsername: e.FollowingUsername,
+			OccurredAt:        e.OccurredAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, responses.GetHistoryResponse{
+		Events: eventResponses,
+	})
+}
+
+func (h *RelationHttp) Block(c *gin.Context) {
+	var req requests.BlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Validation(apperror.CodeInvalidRequest, err.Error()))
+		return
+	}
+
+	actorID, err := util.ResolveActor(c.Request.Context(), req.BlockerID, constant.SCOPE_RELATIONS_WRITE)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = h.relationUc.Block(c.Request.Context(), actorID, req.BlockedID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "block success"})
+}
+
+func (h *RelationHttp) Unblock(c *gin.Context) {
+	var req requests.UnblockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Validation(apperror.CodeInvalidRequest, err.Error()))
+		return
+	}
+
+	actorID, err := util.ResolveActor(c.Request.Context(), req.BlockerID, constant.SCOPE_RELATIONS_WRITE)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = h.relationUc.Unblock(c.Request.Context(), actorID, req.BlockedID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "unblock success"})
+}
+
+// parsePagination reads the page/limit query parameters shared by the list
+// endpoints and writes a 400 response when they are malformed.
+func parsePagination(c *gin.Context) (limit, offset int, ok bool) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.Error(errInvalidPage)
+		return 0, 0, false
+	}
+
+	limit, err = strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		c.Error(errInvalidLimit)
+		return 0, 0, false
+	}
+
+	return limit, (page - 1) * limit, true
+}
+
+// parseCursorPagination reads the limit and decodes the opaque cursor used by
+// keyset pagination. An empty cursor means the first page. Relation cursors
+// point at a follow row, so an id that is not a UUID was tampered with.
+func parseCursorPagination(c *gin.Context, rawCursor string) (limit int, after *util.Cursor, ok bool) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		c.Error(errInvalidLimit)
+		return 0, nil, false
+	}
+
+	if rawCursor == "" {
+		return limit, nil, true
+	}
+
+	after, err = util.DecodeCursor(rawCursor)
+	if err != nil {
+		c.Error(err)
+		return 0, nil, false
+	}
+	if _, err := uuid.Parse(after.ID); err != nil {
+		c.Error(util.ErrInvalidCursor)
+		return 0, nil, false
+	}
+
+	return limit, after, true
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/relations/usecases"
+	"github.com/malikhisyam/user-graph-service/domains/users/models/dto"
+	"github.com/malikhisyam/user-graph-service/shared/constant"
+	"github.com/malikhisyam/user-graph-service/shared/middlewares"
+	"go.uber.org/zap"
+)
+
+// scopedKeys authenticates raw keys to principals holding the mapped scopes.
+type scopedKeys map[string][]string
+
+func (k scopedKeys) Authenticate(ctx context.Context, rawKey string) (*dto.AuthUserDto, error) {
+	return &dto.AuthUserDto{
+		Name:     rawKey,
+		Role:     constant.ROLE_SERVICE,
+		ApiKeyId: uuid.NewString(),
+		Scopes:   k[rawKey],
+	}, nil
+}
+
+// recordingFollows remembers who followed whom; the rest of the interface
+// is left unimplemented.
+type recordingFollows struct {
+	usecases.RelationUseCase
+	followers []uuid.UUID
+}
+
+func (u *recordingFollows) Follow(ctx context.Context, followerID, followingID uuid.UUID) (usecases.FollowStatus, error) {
+	u.followers = append(u.followers, followerID)
+	return usecases.FollowStatusFollowed, nil
+}
+
+func TestFollowWithApiKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	keys := scopedKeys{
+		"writer": {constant.SCOPE_RELATIONS_WRITE},
+		"reader": {constant.SCOPE_RELATIONS_READ},
+	}
+	follower := uuid.New()
+	body := `{"follower_id":"` + follower.String() + `","following_id":"` + uuid.NewString() + `"}`
+
+	tests := []struct {
+		name   string
+		key    string
+		body   string
+		status int
+	}{
+		{name: "write key acting for a user", key: "writer", body: body, status: http.StatusOK},
+		{name: "write key without follower_id", key: "writer", body: `{"following_id":"` + uuid.NewString() + `"}`, status: http.StatusBadRequest},
+		{name: "read key", key: "reader", body: body, status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			relationUc := &recordingFollows{}
+			router := gin.New()
+			router.Use(middlewares.ErrorHandler(zap.NewNop()))
+			router.POST("/followings",
+				middlewares.AuthMiddleware(nil, nil, keys),
+				middlewares.ScopeByMethod(constant.SCOPE_RELATIONS_READ, constant.SCOPE_RELATIONS_WRITE),
+				NewRelationHttp(relationUc).Follow,
+			)
+
+			req := httptest.NewRequest(http.MethodPost, "/followings", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(middlewares.ApiKeyHeader, tt.key)
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+			if tt.status == http.StatusOK && (len(relationUc.followers) != 1 || relationUc.followers[0] != follower) {
+				t.Fatalf("followed as %v, want %s", relationUc.followers, follower)
+			}
+		})
+	}
+}
@@ -2,13 +2,24 @@ package requests
 
 import "github.com/google/uuid"
 
+// FollowerID is taken from the access token; only admin/service callers may
+// set it to act on behalf of another user. API keys must set it and need the
+// relations:write scope.
 type FollowRequest struct {
-	FollowerID  uuid.UUID `json:"follower_id" binding:"required"`
+	FollowerID  uuid.UUID `json:"follower_id"`
 	FollowingID uuid.UUID `json:"following_id" binding:"required"`
 }
 
+// BulkFollowRequest names targets by id, by username or both; FollowerID
+// follows the same rule as in FollowRequest.
+type BulkFollowRequest struct {
+	FollowerID uuid.UUID   `json:"follower_id"`
+	TargetIDs  []uuid.UUID `json:"target_ids"`
+	Usernames  []string    `json:"usernames"`
+}
+
 type UnfollowRequest struct {
-	FollowerID  uuid.UUID `json:"follower_id" binding:"required"`
+	FollowerID  uuid.UUID `json:"follower_id"`
 	FollowingID uuid.UUID `json:"following_id" binding:"required"`
 }
 
@@ -25,3 +36,13 @@ type GetFollowingsRequest struct {
 	UserID uuid.UUID `json:"user_id" binding:"required"`
 }
 
+// BlockerID follows the same rule as FollowRequest.FollowerID.
+type BlockRequest struct {
+	BlockerID uuid.UUID `json:"blocker_id"`
+	BlockedID uuid.UUID `json:"blocked_id" binding:"required"`
+}
+
+type UnblockRequest struct {
+	BlockerID uuid.UUID `json:"blocker_id"`
+	BlockedID uuid.UUID `json:"blocked_id" binding:"required"`
+}
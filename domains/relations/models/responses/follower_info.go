@@ -1,12 +1,17 @@
 package responses
 
-import "time"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type FollowerWithUserInfo struct {
-	ID         string `json:"id"`
-	FollowerID string `json:"follower_id"`
-	Name       string `json:"name"`
-	Username   string `json:"username"`
+	ID         string    `json:"id"`
+	FollowerID string    `json:"follower_id"`
+	Name       string    `json:"name"`
+	Username   string    `json:"username"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
 type FollowingWithUserInfo struct {
@@ -18,3 +23,41 @@ type FollowingWithUserInfo struct {
 	AvatarURL   string
 	CreatedAt   time.Time
 }
+
+type SuggestionWithUserInfo struct {
+	FollowingID string
+	Name        string
+	Username    string
+	AvatarURL   string
+	Score       int
+	FollowedBy  []string `gorm:"-"`
+}
+
+type FollowEventWithUserInfo struct {
+	FollowID          string
+	Event             string
+	FollowerID        string
+	FollowingID       string
+	FollowerUsername  string
+	FollowingUsername string
+	OccurredAt        time.Time
+}
+
+type FollowRequestWithUserInfo struct {
+	ID          string
+	RequesterID string
+	TargetID    string
+	Name        string
+	Username    string
+	CreatedAt   time.Time
+}
+
+// BulkFollowTarget is everything the bulk follow needs to know about one
+// resolved target, loaded for the whole batch in a single query.
+type BulkFollowTarget struct {
+	ID               uuid.UUID
+	Username         string
+	IsPrivate        bool
+	Blocked          bool
+	AlreadyFollowing bool
+}
@@ -1,6 +1,7 @@
 package responses
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -25,9 +26,84 @@ type FollowingResponse struct {
 	CreatedAt    time.Time `json:"created_at"`
 }
 type GetFollowersResponse struct {
-    Followers []FollowerResponse `json:"followers"`
+    Followers  []FollowerResponse `json:"followers"`
+    NextCursor string             `json:"next_cursor,omitempty"`
 }
 
 type GetFollowingsResponse struct {
 	Followings []FollowingResponse `json:"followings"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+type MutualResponse struct {
+	ID          string `json:"id"`
+	UserID      string `json:"user_id"`
+	DisplayName string `json:"display_name"`
+	Username    string `json:"username"`
+}
+
+type GetMutualsResponse struct {
+	Mutuals []MutualResponse `json:"mutuals"`
+}
+
+type SuggestionResponse struct {
+	UserID     string   `json:"user_id"`
+	Name       string   `json:"name"`
+	Username   string   `json:"username"`
+	AvatarURL  string   `json:"avatar_url"`
+	Score      int      `json:"score"`
+	FollowedBy []string `json:"followed_by"`
+}
+
+type GetSuggestionsResponse struct {
+	Suggestions []SuggestionResponse `json:"suggestions"`
+}
+
+type StatsResponse struct {
+	UserID          string `json:"user_id"`
+	FollowersCount  int64  `json:"followers_count"`
+	FollowingsCount int64  `json:"followings_count"`
+}
+
+type FollowEventResponse struct {
+	FollowID          string    `json:"follow_id"`
+	Event             string    `json:"event"`
+	FollowerID        string    `json:"follower_id"`
+	FollowingID       string    `json:"following_id"`
+	FollowerUsername  string    `json:"follower_username"`
+	FollowingUsername string    `json:"following_username"`
+	OccurredAt        time.Time `json:"occurred_at"`
+}
+
+type GetHistoryResponse struct {
+	Events []FollowEventResponse `json:"events"`
+}
+
+type FollowRequestResponse struct {
+	ID          string    `json:"id"`
+	RequesterID string    `json:"requester_id"`
+	TargetID    string    `json:"target_id"`
+	Name        string    `json:"name"`
+	Username    string    `json:"username"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type GetFollowRequestsResponse struct {
+	Requests []FollowRequestResponse `json:"requests"`
+}
+
+type ParkedEventResponse struct {
+	ID           string          `json:"id"`
+	Seq          int64           `json:"seq"`
+	Type         string          `json:"type"`
+	PartitionKey string          `json:"partition_key"`
+	Payload      json.RawMessage `json:"payload"`
+	Attempts     int             `json:"attempts"`
+	CreatedAt    time.Time       `json:"created_at"`
+	FailingSince *time.Time      `json:"failing_since"`
+	ParkedAt     *time.Time      `json:"parked_at"`
+}
+
+type GetParkedEventsResponse struct {
+	Events []ParkedEventResponse `json:"events"`
 }
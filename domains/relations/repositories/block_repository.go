@@ -0,0 +1,198 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/relations/entities"
+	"github.com/malikhisyam/user-graph-service/shared/events"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// pairLockClass is the first key of the advisory locks taken by lockPairs,
+// keeping them apart from single-key locks such as outboxRelayLock.
+const pairLockClass = 7106520
+
+// lockPairs takes a transaction-scoped advisory lock on every pair of userID
+// and one of otherIDs, in ascending key order so concurrent callers cannot
+// deadlock. Follows and blocks of a pair lock it first, so a follow inserted
+// concurrently with a block is either seen and severed by the block or sees
+// the block itself; under READ COMMITTED neither check alone is enough.
+func lockPairs(tx *gorm.DB, userID uuid.UUID, otherIDs ...uuid.UUID) error {
+	keys := make([]int32, 0, len(otherIDs))
+	seen := make(map[int32]bool, len(otherIDs))
+	for _, otherID := range otherIDs {
+		key := pairLockKey(userID, otherID)
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	for _, key := range keys {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", pairLockClass, key).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pairLockKey hashes the unordered pair, so both directions share a lock.
+func pairLockKey(a, b uuid.UUID) int32 {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	hash := fnv.New32a()
+	hash.Write(a[:])
+	hash.Write(b[:])
+	return int32(hash.Sum32())
+}
+
+func (r *relationRepository) Block(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	r.logger.Info("Attempting to block user",
+		zap.String("blocker_id", blockerID.String()),
+		zap.String("blocked_id", blockedID.String()),
+	)
+
+	err := r.db.GetInstance().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockPairs(tx, blockerID, blockedID); err != nil {
+			return err
+		}
+
+		block := entities.Blocks{
+			ID:        uuid.New(),
+			BlockerID: blockerID,
+			BlockedID: blockedID,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		// idx_blocks_blocker_blocked settles concurrent blocks of the same
+		// pair; the loser sees no row written.
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&block)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAlreadyBlocked
+		}
+
+		// Sever the follow edges in both directions so neither side keeps
+		// seeing the other in their lists.
+		var severed []entities.Follows
+		err := tx.
+			Where("(follower_id = ? AND following_id = ?) OR (follower_id = ? AND following_id = ?)",
+				blockerID, blockedID, blockedID, blockerID).
+			Find(&severed).Error
+		if err != nil {
+			return err
+		}
+
+		changes := make([]followEvent, 0, len(severed))
+		for _, edge := range severed {
+			if err := tx.Delete(&edge).Error; err != nil {
+				return err
+			}
+			if err := adjustFollowCounts(tx, edge.FollowerID, edge.FollowingID, -1); err != nil {
+				return err
+			}
+			changes = append(changes, followEvent{
+				Type:        events.FollowDeleted,
+				FollowID:    edge.ID,
+				FollowerID:  edge.FollowerID,
+				FollowingID: edge.FollowingID,
+				OccurredAt:  time.Now(),
+			})
+		}
+		if err := enqueueFollowEvents(tx, changes...); err != nil {
+			return err
+		}
+
+		return tx.
+			Where("(requester_id = ? AND target_id = ?) OR (requester_id = ? AND target_id = ?)",
+				blockerID, blockedID, blockedID, blockerID).
+			Delete(&entities.FollowRequests{}).Error
+	})
+	if err != nil {
+		r.logger.Error("Failed to block user",
+			zap.Error(err),
+			zap.String("blocker_id", blockerID.String()),
+			zap.String("blocked_id", blockedID.String()),
+		)
+		return err
+	}
+
+	for _, cacheKey := range []string{followKey(blockerID, blockedID), followKey(blockedID, blockerID)} {
+		if err := r.redisCache.Del(ctx, cacheKey).Err(); err != nil {
+			r.logger.Error("Failed to delete follow relationship from Redis cache",
+				zap.Error(err),
+				zap.String("cache_key", cacheKey),
+			)
+		}
+	}
+	invalidateStats(ctx, r.redisCache, r.logger, blockerID, blockedID)
+
+	r.logger.Info("User blocked successfully",
+		zap.String("blocker_id", blockerID.String()),
+		zap.String("blocked_id", blockedID.String()),
+	)
+	return nil
+}
+
+func (r *relationRepository) Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	result := r.db.GetInstance().
+		Unscoped().
+		WithContext(ctx).
+		Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+		Delete(&entities.Blocks{})
+
+	if result.Error != nil {
+		r.logger.Error("Failed to delete block relationship from database",
+			zap.Error(result.Error),
+			zap.String("blocker_id", blockerID.String()),
+			zap.String("blocked_id", blockedID.String()),
+		)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		r.logger.Warn("Unblock attempt on a non-existent block",
+			zap.String("blocker_id", blockerID.String()),
+			zap.String("blocked_id", blockedID.String()),
+		)
+		return ErrNotBlocked
+	}
+
+	r.logger.Info("User unblocked successfully",
+		zap.String("blocker_id", blockerID.String()),
+		zap.String("blocked_id", blockedID.String()),
+	)
+	return nil
+}
+
+// IsBlocked reports whether either user has blocked the other.
+func (r *relationRepository) IsBlocked(ctx context.Context, userID, otherID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.GetInstance().
+		WithContext(ctx).
+		Model(&entities.Blocks{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)",
+			userID, otherID, otherID, userID).
+		Count(&count).Error
+	if err != nil {
+		r.logger.Error("Database error during IsBlocked check",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+			zap.String("other_id", otherID.String()),
+		)
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/relations/models/responses"
+	"github.com/malikhisyam/user-graph-service/shared/events"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ResolveBulkTargets looks the targets up by id or case-insensitive username
+// and reports, relative to followerID, whether each is private, blocked in
+// either direction or already followed. Unknown and deactivated accounts are
+// simply missing from the result.
+func (r *relationRepository) ResolveBulkTargets(ctx context.Context, followerID uuid.UUID, targetIDs []uuid.UUID, usernames []string) ([]responses.BulkFollowTarget, error) {
+	var targets []responses.BulkFollowTarget
+
+	ids := append([]uuid.UUID{uuid.Nil}, targetIDs...)
+	names := []string{""}
+	for _, username := range usernames {
+		names = append(names, strings.ToLower(strings.TrimSpace(username)))
+	}
+
+	err := r.db.GetInstance().WithContext(ctx).Raw(`
+		SELECT
+			u.id,
+			u.username,
+			u.is_private,
+			EXISTS (
+				SELECT 1 FROM blocks b
+				WHERE (b.blocker_id = @follower AND b.blocked_id = u.id)
+					OR (b.blocker_id = u.id AND b.blocked_id = @follower)
+			) AS blocked,
+			EXISTS (
+				SELECT 1 FROM follows f
+				WHERE f.follower_id = @follower AND f.following_id = u.id AND f.deleted_at IS NULL
+			) AS already_following
+		FROM users u
+		WHERE u.deactivated_at IS NULL
+			AND (u.id IN @ids OR LOWER(u.username) IN @names)`,
+		map[string]interface{}{"follower": followerID, "ids": ids, "names": names},
+	).Scan(&targets).Error
+	if err != nil {
+		r.logger.Error("Failed to resolve bulk follow targets",
+			zap.Error(err),
+			zap.String("follower_id", followerID.String()),
+		)
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+// BulkFollow creates the follows to every target in one statement and
+// returns the targets actually followed. ON CONFLICT on the live-edge index
+// skips pairs that became follows concurrently, and the block check is
+// repeated in SQL, under the pair locks, for the same reason. Counters move
+// in the same transaction: once for the follower, once per followed target.
+func (r *relationRepository) BulkFollow(ctx context.Context, followerID uuid.UUID, targetIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if len(targetIDs) == 0 {
+		return nil, nil
+	}
+
+	var inserted []struct {
+		ID          uuid.UUID
+		FollowingID uuid.UUID
+	}
+	var created []uuid.UUID
+
+	err := r.db.GetInstance().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockPairs(tx, followerID, targetIDs...); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		err := tx.Raw(`
+			INSERT INTO follows (id, follower_id, following_id, created_at, updated_at)
+			SELECT uuid_generate_v4(), @follower, u.id, @now, @now
+			FROM users u
+			WHERE u.id IN @targets
+				AND u.id <> @follower
+				AND NOT EXISTS (
+					SELECT 1 FROM blocks b
+					WHERE (b.blocker_id = @follower AND b.blocked_id = u.id)
+						OR (b.blocker_id = u.id AND b.blocked_id = @follower)
+				)
+			ON CONFLICT (follower_id, following_id) WHERE deleted_at IS NULL DO NOTHING
+			RETURNING id, following_id`,
+			map[string]interface{}{"follower": followerID, "targets": targetIDs, "now": now},
+		).Scan(&inserted).Error
+		if err != nil || len(inserted) == 0 {
+			return err
+		}
+
+		changes := make([]followEvent, 0, len(inserted))
+		for _, edge := range inserted {
+			created = append(created, edge.FollowingID)
+			changes = append(changes, followEvent{
+				Type:        events.FollowCreated,
+				FollowID:    edge.ID,
+				FollowerID:  followerID,
+				FollowingID: edge.FollowingID,
+				OccurredAt:  now,
+			})
+		}
+		if err := enqueueFollowEvents(tx, changes...); err != nil {
+			return err
+		}
+
+		err = tx.Exec(upsertFollowingsCountSQL, map[string]interface{}{"user": followerID, "delta": len(created)}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			INSERT INTO user_stats (user_id, followers_count, followings_count, updated_at)
+			SELECT
+				u.id,
+				(SELECT COUNT(*) FROM follows WHERE following_id = u.id AND deleted_at IS NULL),
+				(SELECT COUNT(*) FROM follows WHERE follower_id = u.id AND deleted_at IS NULL),
+				NOW()
+			FROM users u
+			WHERE u.id IN @targets
+			ON CONFLICT (user_id) DO UPDATE SET
+				followers_count = user_stats.followers_count + 1,
+				updated_at = NOW()`,
+			map[string]interface{}{"targets": created},
+		).Error
+	})
+	if err != nil {
+		r.logger.Error("Failed to create bulk follow relationships",
+			zap.Error(err),
+			zap.String("follower_id", followerID.String()),
+			zap.Int("targets", len(targetIDs)),
+		)
+		return nil, err
+	}
+
+	if len(created) > 0 {
+		pipe := r.redisCache.Pipeline()
+		for _, followingID := range created {
+			pipe.Set(ctx, followKey(followerID, followingID), "1", 10*time.Minute)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			r.logger.Error("Failed to cache bulk follow relationships",
+				zap.Error(err),
+				zap.String("follower_id", followerID.String()),
+			)
+		}
+		invalidateStats(ctx, r.redisCache, r.logger, append([]uuid.UUID{followerID}, created...)...)
+	}
+
+	r.logger.Info("Bulk follow completed",
+		zap.String("follower_id", followerID.String()),
+		zap.Int("requested", len(targetIDs)),
+		zap.Int("created", len(created)),
+	)
+	return created, nil
+}
+
+// BulkCreateFollowRequests sends follow requests to private targets, leaving
+// requests that already exist untouched.
+func (r *relationRepository) BulkCreateFollowRequests(ctx context.Context, requesterID uuid.UUID, targetIDs []uuid.UUID) error {
+	if len(targetIDs) == 0 {
+		return nil
+	}
+
+	err := r.db.GetInstance().WithContext(ctx).Exec(`
+		INSERT INTO follow_requests (id, requester_id, target_id, created_at, updated_at)
+		SELECT uuid_generate_v4(), @requester, u.id, @now, @now
+		FROM users u
+		WHERE u.id IN @targets AND u.id <> @requester
+		ON CONFLICT (requester_id, target_id) DO NOTHING`,
+		map[string]interface{}{"requester": requesterID, "targets": targetIDs, "now": time.Now()},
+	).Error
+	if err != nil {
+		r.logger.Error("Failed to create bulk follow requests",
+			zap.Error(err),
+			zap.String("requester_id", requesterID.String()),
+			zap.Int("targets", len(targetIDs)),
+		)
+		return err
+	}
+
+	return nil
+}
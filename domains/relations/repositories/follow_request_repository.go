@@ -0,0 +1,224 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/relations/entities"
+	"github.com/malikhisyam/user-graph-service/domains/relations/models/responses"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *relationRepository) IsPrivateAccount(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var isPrivate bool
+	result := r.db.GetInstance().
+		WithContext(ctx).
+		Table("users").
+		Select("is_private").
+		Where("id = ?", userID).
+		Scan(&isPrivate)
+	if result.Error != nil {
+		r.logger.Error("Failed to check account privacy",
+			zap.Error(result.Error),
+			zap.String("user_id", userID.String()),
+		)
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, ErrUserNotFound
+	}
+
+	return isPrivate, nil
+}
+
+func (r *relationRepository) CreateFollowRequest(ctx context.Context, requesterID, targetID uuid.UUID) error {
+	blocked, err := r.IsBlocked(ctx, requesterID, targetID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		r.logger.Warn("Attempted to request a follow across a block",
+			zap.String("requester_id", requesterID.String()),
+			zap.String("target_id", targetID.String()),
+		)
+		return ErrFollowBlocked
+	}
+
+	isFollowing, err := r.IsFollowing(ctx, requesterID, targetID)
+	if err != nil {
+		return err
+	}
+	if isFollowing {
+		return ErrAlreadyFollowing
+	}
+
+	request := entities.FollowRequests{
+		ID:          uuid.New(),
+		RequesterID: requesterID,
+		TargetID:    targetID,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	// The unique (requester_id, target_id) index settles concurrent requests.
+	result := r.db.GetInstance().WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&request)
+	if result.Error != nil {
+		r.logger.Error("Failed to create follow request in database",
+			zap.Error(result.Error),
+			zap.String("requester_id", requesterID.String()),
+			zap.String("target_id", targetID.String()),
+		)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		r.logger.Warn("Attempted to create a follow request that already exists",
+			zap.String("requester_id", requesterID.String()),
+			zap.String("target_id", targetID.String()),
+		)
+		return ErrFollowRequestExists
+	}
+
+	r.logger.Info("Follow request created successfully",
+		zap.String("request_id", request.ID.String()),
+		zap.String("requester_id", requesterID.String()),
+		zap.String("target_id", targetID.String()),
+	)
+	return nil
+}
+
+func (r *relationRepository) GetFollowRequest(ctx context.Context, requestID uuid.UUID) (*entities.FollowRequests, error) {
+	var request entities.FollowRequests
+	err := r.db.GetInstance().
+		WithContext(ctx).
+		Where("id = ?", requestID).
+		First(&request).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFollowRequestNotFound
+		}
+		r.logger.Error("Failed to load follow request",
+			zap.Error(err),
+			zap.String("request_id", requestID.String()),
+		)
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+// ApproveFollowRequest turns a pending request into a follow edge in a
+// single transaction so the request can never be both consumed and lost.
+func (r *relationRepository) ApproveFollowRequest(ctx context.Context, requestID uuid.UUID) (*entities.FollowRequests, error) {
+	var request entities.FollowRequests
+
+	err := r.db.GetInstance().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("id = ?", requestID).First(&request).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrFollowRequestNotFound
+			}
+			return err
+		}
+
+		if err := tx.Delete(&request).Error; err != nil {
+			return err
+		}
+
+		_, err = insertFollow(tx, request.RequesterID, request.TargetID)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("Failed to approve follow request",
+			zap.Error(err),
+			zap.String("request_id", requestID.String()),
+		)
+		return nil, err
+	}
+
+	cacheKey := followKey(request.RequesterID, request.TargetID)
+	if err := r.redisCache.Set(ctx, cacheKey, "1", 10*time.Minute).Err(); err != nil {
+		r.logger.Error("Failed to set follow relationship in Redis cache",
+			zap.Error(err),
+			zap.String("cache_key", cacheKey),
+		)
+	}
+	invalidateStats(ctx, r.redisCache, r.logger, request.RequesterID, request.TargetID)
+
+	r.logger.Info("Follow request approved successfully",
+		zap.String("request_id", requestID.String()),
+		zap.String("follower_id", request.RequesterID.String()),
+		zap.String("following_id", request.TargetID.String()),
+	)
+	return &request, nil
+}
+
+func (r *relationRepository) DeleteFollowRequest(ctx context.Context, requestID uuid.UUID) error {
+	result := r.db.GetInstance().
+		WithContext(ctx).
+		Where("id = ?", requestID).
+		Delete(&entities.FollowRequests{})
+
+	if result.Error != nil {
+		r.logger.Error("Failed to delete follow request from database",
+			zap.Error(result.Error),
+			zap.String("request_id", requestID.String()),
+		)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		r.logger.Warn("Delete attempt on a non-existent follow request",
+			zap.String("request_id", requestID.String()),
+		)
+		return ErrFollowRequestNotFound
+	}
+
+	r.logger.Info("Follow request deleted successfully",
+		zap.String("request_id", requestID.String()),
+	)
+	return nil
+}
+
+func (r *relationRepository) GetIncomingFollowRequests(ctx context.Context, userID uuid.UUID, limit, offset int) ([]responses.FollowRequestWithUserInfo, error) {
+	var results []responses.FollowRequestWithUserInfo
+
+	err := r.db.GetInstance().WithContext(ctx).
+		Table("follow_requests AS fr").
+		Select("fr.id, fr.requester_id, fr.target_id, u.name, u.username, fr.created_at").
+		Joins("JOIN users u ON fr.requester_id = u.id AND u.deactivated_at IS NULL").
+		Where("fr.target_id = ?", userID).
+		Order("fr.created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&results).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (r *relationRepository) GetOutgoingFollowRequests(ctx context.Context, userID uuid.UUID, limit, offset int) ([]responses.FollowRequestWithUserInfo, error) {
+	var results []responses.FollowRequestWithUserInfo
+
+	err := r.db.GetInstance().WithContext(ctx).
+		Table("follow_requests AS fr").
+		Select("fr.id, fr.requester_id, fr.target_id, u.name, u.username, fr.created_at").
+		Joins("JOIN users u ON fr.target_id = u.id AND u.deactivated_at IS NULL").
+		Where("fr.requester_id = ?", userID).
+		Order("fr.created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&results).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/relations/models/responses"
+	"go.uber.org/zap"
+)
+
+// GetHistory lists follow and unfollow events touching userID, newest first.
+// Every follows row, live or soft-deleted, yields a follow event at its
+// created_at and, once deleted, an unfollow event at its deleted_at.
+func (r *relationRepository) GetHistory(ctx context.Context, userID uuid.UUID, limit, offset int) ([]responses.FollowEventWithUserInfo, error) {
+	var events []responses.FollowEventWithUserInfo
+
+	err := r.db.GetInstance().WithContext(ctx).Raw(`
+		SELECT
+			e.follow_id,
+			e.event,
+			e.follower_id,
+			e.following_id,
+			follower.username AS follower_username,
+			following.username AS following_username,
+			e.occurred_at
+		FROM (
+			SELECT id AS follow_id, 'follow' AS event, follower_id, following_id, created_at AS occurred_at
+			FROM follows
+			WHERE follower_id = @user OR following_id = @user
+			UNION ALL
+			SELECT id AS follow_id, 'unfollow' AS event, follower_id, following_id, deleted_at AS occurred_at
+			FROM follows
+			WHERE (follower_id = @user OR following_id = @user) AND deleted_at IS NOT NULL
+		) e
+		JOIN users follower ON follower.id = e.follower_id
+		JOIN users following ON following.id = e.following_id
+		ORDER BY e.occurred_at DESC, e.follow_id DESC, e.event DESC
+		LIMIT @limit OFFSET @offset`,
+		map[string]interface{}{"user": userID, "limit": limit, "offset": offset},
+	).Scan(&events).Error
+	if err != nil {
+		r.logger.Error("Failed to load follow history",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+		)
+		return nil, err
+	}
+
+	return events, nil
+}
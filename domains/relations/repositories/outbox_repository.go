@@ -0,0 +1,274 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/relations/entities"
+	"github.com/malikhisyam/user-graph-service/shared/events"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// outboxRelayLock is the advisory lock id taken by a relay run, so several
+// instances never interleave and reorder one follower's events.
+const outboxRelayLock = 7106519
+
+// A failed event waits outboxRetryBase before its second attempt, twice as
+// long before each later one, and never more than outboxRetryMax.
+const (
+	outboxRetryBase = time.Second
+	outboxRetryMax  = 5 * time.Minute
+)
+
+// followEvent describes one edge change for the outbox.
+type followEvent struct {
+	Type        string
+	FollowID    uuid.UUID
+	FollowerID  uuid.UUID
+	FollowingID uuid.UUID
+	OccurredAt  time.Time
+}
+
+// enqueueFollowEvents records the events in the caller's transaction, so they
+// are stored if and only if the edge change commits.
+func enqueueFollowEvents(tx *gorm.DB, changes ...followEvent) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	rows := make([]entities.Outbox, 0, len(changes))
+	for _, change := range changes {
+		payload, err := json.Marshal(map[string]interface{}{
+			"follow_id":    change.FollowID.String(),
+			"follower_id":  change.FollowerID.String(),
+			"following_id": change.FollowingID.String(),
+			"occurred_at":  change.OccurredAt.UTC(),
+		})
+		if err != nil {
+			return err
+		}
+
+		rows = append(rows, entities.Outbox{
+			ID:           uuid.New(),
+			Type:         change.Type,
+			PartitionKey: change.FollowerID,
+			Payload:      string(payload),
+			CreatedAt:    change.OccurredAt,
+		})
+	}
+
+	return tx.Create(&rows).Error
+}
+
+// RelayOutbox hands up to limit due events to publish in Seq order and marks
+// the accepted ones published, under a transaction-scoped advisory lock. A
+// failed event is retried with exponential backoff, and until it goes out
+// its follower's later events wait while other followers go on. A crash
+// after publishing but before commit publishes again, which is what makes
+// delivery at least once.
+//
+// An event still failing parkAfter after its first failure is parked: it is
+// no longer retried on its own, and its follower stays held until an
+// operator replays it. parkAfter 0 never parks.
+func (r *relationRepository) RelayOutbox(ctx context.Context, limit int, parkAfter time.Duration, publish func(context.Context, events.Event) error) (int, error) {
+	published := 0
+
+	err := r.db.GetInstance().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", outboxRelayLock).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+
+		// Times are passed from Go, the way they were written, because the
+		// columns have no time zone.
+		now := time.Now()
+		var pending []pendingOutbox
+		err := tx.Raw(`
+			SELECT o.*, COALESCE(@park AND o.failing_since < @park_before, false) AS overdue
+			FROM outbox o
+			WHERE o.published_at IS NULL
+				AND o.parked_at IS NULL
+				AND (o.next_attempt_at IS NULL OR o.next_attempt_at <= @now)
+				AND NOT EXISTS (
+					SELECT 1 FROM outbox p
+					WHERE p.partition_key = o.partition_key
+						AND p.published_at IS NULL
+						AND p.seq < o.seq
+				)
+			ORDER BY o.seq
+			LIMIT @limit`,
+			map[string]interface{}{
+				"now":         now,
+				"park":        parkAfter > 0,
+				"park_before": now.Add(-parkAfter),
+				"limit":       limit,
+			},
+		).Scan(&pending).Error
+		if err != nil {
+			return err
+		}
+
+		var done []int64
+		for _, row := range pending {
+			if err := publish(ctx, outboxEvent(row.Outbox)); err != nil {
+				r.logger.Error("Failed to publish outbox event",
+					zap.Error(err),
+					zap.Int64("seq", row.Seq),
+					zap.String("type", row.Type),
+					zap.Int("attempts", row.Attempts+1),
+				)
+				if err := r.retryOutbox(tx, row, now); err != nil {
+					return err
+				}
+				continue
+			}
+			done = append(done, row.Seq)
+		}
+
+		if len(done) > 0 {
+			err := tx.Model(&entities.Outbox{}).
+				Where("seq IN ?", done).
+				Update("published_at", now).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		published = len(done)
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("Failed to relay outbox", zap.Error(err))
+		return 0, err
+	}
+
+	return published, nil
+}
+
+// pendingOutbox is a row due for publishing; Overdue is set once it has been
+// failing for longer than the relay's park deadline.
+type pendingOutbox struct {
+	entities.Outbox
+	Overdue bool
+}
+
+// retryOutbox records a failed publish: the row is parked when overdue and
+// otherwise scheduled again after a backoff.
+func (r *relationRepository) retryOutbox(tx *gorm.DB, row pendingOutbox, now time.Time) error {
+	updates := map[string]interface{}{
+		"attempts":      gorm.Expr("attempts + 1"),
+		"failing_since": gorm.Expr("COALESCE(failing_since, ?)", now),
+	}
+	if row.Overdue {
+		r.logger.Error("Parking outbox event, its follower is held until it is replayed",
+			zap.Int64("seq", row.Seq),
+			zap.String("type", row.Type),
+			zap.String("partition_key", row.PartitionKey.String()),
+		)
+		updates["parked_at"] = now
+	} else {
+		updates["next_attempt_at"] = now.Add(outboxBackoff(row.Attempts + 1))
+	}
+
+	return tx.Model(&entities.Outbox{}).Where("seq = ?", row.Seq).Updates(updates).Error
+}
+
+// outboxBackoff doubles the wait after every failed attempt, from
+// outboxRetryBase up to outboxRetryMax.
+func outboxBackoff(attempts int) time.Duration {
+	wait := outboxRetryBase
+	for i := 1; i < attempts && wait < outboxRetryMax; i++ {
+		wait *= 2
+	}
+	if wait > outboxRetryMax {
+		wait = outboxRetryMax
+	}
+	return wait
+}
+
+// ListParkedOutbox returns parked events, oldest first.
+func (r *relationRepository) ListParkedOutbox(ctx context.Context, limit, offset int) ([]entities.Outbox, error) {
+	var parked []entities.Outbox
+	err := r.db.GetInstance().WithContext(ctx).
+		Where("parked_at IS NOT NULL").
+		Order("seq").
+		Limit(limit).
+		Offset(offset).
+		Find(&parked).Error
+	if err != nil {
+		r.logger.Error("Failed to list parked outbox events", zap.Error(err))
+		return nil, err
+	}
+
+	return parked, nil
+}
+
+// ReplayParkedOutbox hands parked events back to the relay with a fresh
+// retry budget: the one with eventID, or all of them when it is uuid.Nil.
+// It reports how many were replayed and fails with ErrOutboxEventNotFound
+// when eventID names no parked event.
+func (r *relationRepository) ReplayParkedOutbox(ctx context.Context, eventID uuid.UUID) (int, error) {
+	query := r.db.GetInstance().WithContext(ctx).
+		Model(&entities.Outbox{}).
+		Where("parked_at IS NOT NULL")
+	if eventID != uuid.Nil {
+		query = query.Where("id = ?", eventID)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"parked_at":       nil,
+		"failing_since":   nil,
+		"next_attempt_at": nil,
+		"attempts":        0,
+	})
+	if result.Error != nil {
+		r.logger.Error("Failed to replay parked outbox events", zap.Error(result.Error))
+		return 0, result.Error
+	}
+	if eventID != uuid.Nil && result.RowsAffected == 0 {
+		return 0, ErrOutboxEventNotFound
+	}
+
+	r.logger.Info("Parked outbox events replayed", zap.Int64("replayed", result.RowsAffected))
+	return int(result.RowsAffected), nil
+}
+
+// PruneOutbox deletes up to limit events published before publishedBefore
+// and reports how many went. Parked events are kept.
+func (r *relationRepository) PruneOutbox(ctx context.Context, publishedBefore time.Time, limit int) (int, error) {
+	result := r.db.GetInstance().WithContext(ctx).Exec(`
+		DELETE FROM outbox
+		WHERE seq IN (
+			SELECT seq FROM outbox
+			WHERE published_at < @before
+			ORDER BY seq
+			LIMIT @limit
+		)`,
+		map[string]interface{}{"before": publishedBefore, "limit": limit},
+	)
+	if result.Error != nil {
+		r.logger.Error("Failed to prune outbox", zap.Error(result.Error))
+		return 0, result.Error
+	}
+
+	return int(result.RowsAffected), nil
+}
+
+func outboxEvent(row entities.Outbox) events.Event {
+	var data map[string]interface{}
+	_ = json.Unmarshal([]byte(row.Payload), &data)
+
+	return events.Event{
+		ID:         row.ID.String(),
+		Key:        row.PartitionKey.String(),
+		Type:       row.Type,
+		OccurredAt: row.CreatedAt.UTC(),
+		Data:       data,
+	}
+}
@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/shared/events"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// purgeFollowsSQL hard-deletes one batch of the user's edges, live or soft
+// deleted, and takes the live ones off the counters of the users on the
+// other end. The user's own stats row is dropped once nothing is left.
+const purgeFollowsSQL = `
+	WITH batch AS (
+		SELECT id FROM follows
+		WHERE follower_id = @user OR following_id = @user
+		LIMIT @batch
+	),
+	removed AS (
+		DELETE FROM follows f USING batch
+		WHERE f.id = batch.id
+		RETURNING f.id, f.follower_id, f.following_id, f.deleted_at
+	),
+	lost_followers AS (
+		UPDATE user_stats s
+		SET followers_count = GREATEST(s.followers_count - d.n, 0), updated_at = NOW()
+		FROM (
+			SELECT following_id AS user_id, COUNT(*) AS n FROM removed
+			WHERE follower_id = @user AND deleted_at IS NULL
+			GROUP BY following_id
+		) d
+		WHERE s.user_id = d.user_id
+	),
+	lost_followings AS (
+		UPDATE user_stats s
+		SET followings_count = GREATEST(s.followings_count - d.n, 0), updated_at = NOW()
+		FROM (
+			SELECT follower_id AS user_id, COUNT(*) AS n FROM removed
+			WHERE following_id = @user AND deleted_at IS NULL
+			GROUP BY follower_id
+		) d
+		WHERE s.user_id = d.user_id
+	)
+	SELECT id, follower_id, following_id, deleted_at FROM removed`
+
+type purgedEdge struct {
+	ID          uuid.UUID
+	FollowerID  uuid.UUID
+	FollowingID uuid.UUID
+	DeletedAt   *time.Time
+}
+
+// PurgeUserBatch removes up to batchSize of the user's follows, then blocks,
+// then follow requests, and reports how many rows went. Callers loop until it
+// returns zero; every batch is its own short transaction so a user with
+// millions of followers never holds long locks.
+func (r *relationRepository) PurgeUserBatch(ctx context.Context, userID uuid.UUID, batchSize int) (int, error) {
+	var edges []purgedEdge
+
+	err := r.db.GetInstance().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Raw(purgeFollowsSQL, map[string]interface{}{"user": userID, "batch": batchSize}).
+			Scan(&edges).Error
+		if err != nil {
+			return err
+		}
+
+		// Only live edges are announced; soft-deleted ones were already.
+		now := time.Now()
+		var changes []followEvent
+		for _, edge := range edges {
+			if edge.DeletedAt != nil {
+				continue
+			}
+			changes = append(changes, followEvent{
+				Type:        events.FollowDeleted,
+				FollowID:    edge.ID,
+				FollowerID:  edge.FollowerID,
+				FollowingID: edge.FollowingID,
+				OccurredAt:  now,
+			})
+		}
+		return enqueueFollowEvents(tx, changes...)
+	})
+	if err != nil {
+		r.logger.Error("Failed to purge follows of user",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+		)
+		return 0, err
+	}
+
+	if len(edges) > 0 {
+		r.invalidatePurgedEdges(ctx, userID, edges)
+		return len(edges), nil
+	}
+
+	for _, table := range []struct{ name, left, right string }{
+		{"blocks", "blocker_id", "blocked_id"},
+		{"follow_requests", "requester_id", "target_id"},
+	} {
+		result := r.db.GetInstance().WithContext(ctx).Exec(
+			"DELETE FROM "+table.name+" WHERE id IN (SELECT id FROM "+table.name+
+				" WHERE "+table.left+" = @user OR "+table.right+" = @user LIMIT @batch)",
+			map[string]interface{}{"user": userID, "batch": batchSize},
+		)
+		if result.Error != nil {
+			r.logger.Error("Failed to purge rows of user",
+				zap.Error(result.Error),
+				zap.String("table", table.name),
+				zap.String("user_id", userID.String()),
+			)
+			return 0, result.Error
+		}
+		if result.RowsAffected > 0 {
+			return int(result.RowsAffected), nil
+		}
+	}
+
+	if err := r.db.GetInstance().WithContext(ctx).Exec("DELETE FROM user_stats WHERE user_id = ?", userID).Error; err != nil {
+		r.logger.Error("Failed to purge stats of user",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+		)
+		return 0, err
+	}
+	invalidateStats(ctx, r.redisCache, r.logger, userID)
+
+	return 0, nil
+}
+
+func (r *relationRepository) invalidatePurgedEdges(ctx context.Context, userID uuid.UUID, edges []purgedEdge) {
+	keys := make([]string, 0, len(edges))
+	others := make([]uuid.UUID, 0, len(edges))
+	for _, edge := range edges {
+		keys = append(keys, followKey(edge.FollowerID, edge.FollowingID))
+		if edge.FollowerID == userID {
+			others = append(others, edge.FollowingID)
+		} else {
+			others = append(others, edge.FollowerID)
+		}
+	}
+
+	if err := r.redisCache.Del(ctx, keys...).Err(); err != nil {
+		r.logger.Error("Failed to invalidate follow cache after purge",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+			zap.Int("keys", len(keys)),
+		)
+	}
+	invalidateStats(ctx, r.redisCache, r.logger, others...)
+}
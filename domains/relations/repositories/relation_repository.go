@@ -4,25 +4,61 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/malikhisyam/user-graph-service/domains/relations/entities"
 	"github.com/malikhisyam/user-graph-service/domains/relations/models/responses"
 	"github.com/malikhisyam/user-graph-service/infrastructures"
+	"github.com/malikhisyam/user-graph-service/shared/apperror"
+	"github.com/malikhisyam/user-graph-service/shared/events"
 	"github.com/malikhisyam/user-graph-service/shared/util"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrUserNotFound          = apperror.NotFound("user_not_found", "user not found")
+	ErrFollowBlocked         = apperror.Unprocessable("follow_blocked", "follow not allowed between blocked users")
+	ErrAlreadyFollowing      = apperror.Conflict("already_following", "user already following")
+	ErrNotFollowing          = apperror.NotFound("not_following", "follow relationship not found")
+	ErrFollowRequestExists   = apperror.Conflict("follow_request_exists", "follow request already sent")
+	ErrFollowRequestNotFound = apperror.NotFound("follow_request_not_found", "follow request not found")
+	ErrAlreadyBlocked        = apperror.Conflict("already_blocked", "user already blocked")
+	ErrNotBlocked            = apperror.NotFound("not_blocked", "block relationship not found")
+	ErrOutboxEventNotFound   = apperror.NotFound("outbox_event_not_found", "parked outbox event not found")
+)
+
 type RelationRepository interface {
-	Follow(ctx context.Context, followerID, followingID uuid.UUID) error
+	Follow(ctx context.Context, followerID, followingID uuid.UUID) (bool, error)
 	Unfollow(ctx context.Context, followerID, followingID uuid.UUID) error
 	IsFollowing(ctx context.Context, followerID, followingID uuid.UUID) (bool, error)
-	GetFollowers(ctx context.Context, userID string, limit, offset int, nameFilter string) ([]responses.FollowerWithUserInfo, error)
-	GetFollowings(ctx context.Context, userID string, limit, offset int, nameFilter string) ([]responses.FollowingWithUserInfo, error)
+	GetFollowers(ctx context.Context, userID uuid.UUID, limit, offset int, nameFilter string) ([]responses.FollowerWithUserInfo, error)
+	GetFollowings(ctx context.Context, userID uuid.UUID, limit, offset int, nameFilter string) ([]responses.FollowingWithUserInfo, error)
+	GetFollowersByCursor(ctx context.Context, userID uuid.UUID, limit int, after *util.Cursor, nameFilter string) ([]responses.FollowerWithUserInfo, error)
+	GetFollowingsByCursor(ctx context.Context, userID uuid.UUID, limit int, after *util.Cursor, nameFilter string) ([]responses.FollowingWithUserInfo, error)
+	GetMutuals(ctx context.Context, userID uuid.UUID, limit, offset int, nameFilter string) ([]responses.FollowerWithUserInfo, error)
+	GetSuggestions(ctx context.Context, userID uuid.UUID, limit int) ([]responses.SuggestionWithUserInfo, error)
+	GetHistory(ctx context.Context, userID uuid.UUID, limit, offset int) ([]responses.FollowEventWithUserInfo, error)
+	Block(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	IsBlocked(ctx context.Context, userID, otherID uuid.UUID) (bool, error)
+	IsPrivateAccount(ctx context.Context, userID uuid.UUID) (bool, error)
+	CreateFollowRequest(ctx context.Context, requesterID, targetID uuid.UUID) error
+	GetFollowRequest(ctx context.Context, requestID uuid.UUID) (*entities.FollowRequests, error)
+	ApproveFollowRequest(ctx context.Context, requestID uuid.UUID) (*entities.FollowRequests, error)
+	DeleteFollowRequest(ctx context.Context, requestID uuid.UUID) error
+	GetIncomingFollowRequests(ctx context.Context, userID uuid.UUID, limit, offset int) ([]responses.FollowRequestWithUserInfo, error)
+	GetOutgoingFollowRequests(ctx context.Context, userID uuid.UUID, limit, offset int) ([]responses.FollowRequestWithUserInfo, error)
+	PurgeUserBatch(ctx context.Context, userID uuid.UUID, batchSize int) (int, error)
+	ResolveBulkTargets(ctx context.Context, followerID uuid.UUID, targetIDs []uuid.UUID, usernames []string) ([]responses.BulkFollowTarget, error)
+	BulkFollow(ctx context.Context, followerID uuid.UUID, targetIDs []uuid.UUID) ([]uuid.UUID, error)
+	BulkCreateFollowRequests(ctx context.Context, requesterID uuid.UUID, targetIDs []uuid.UUID) error
+	RelayOutbox(ctx context.Context, limit int, parkAfter time.Duration, publish func(context.Context, events.Event) error) (int, error)
+	ListParkedOutbox(ctx context.Context, limit, offset int) ([]entities.Outbox, error)
+	ReplayParkedOutbox(ctx context.Context, eventID uuid.UUID) (int, error)
+	PruneOutbox(ctx context.Context, publishedBefore time.Time, limit int) (int, error)
 }
 
 type relationRepository struct {
@@ -44,50 +80,32 @@ func followKey(followerID, followingID uuid.UUID) string {
 }
 
 
-func (r *relationRepository) Follow(ctx context.Context, followerID, followingID uuid.UUID) error {
-	var existing entities.Follows
-
-	err := r.db.GetInstance().
-		WithContext(ctx).
-		Where("follower_id = ? AND following_id = ?", followerID, followingID).
-		First(&existing).Error
-
-	if err == nil {
-		r.logger.Warn("Attempted to create a follow relationship that already exists",
-			zap.String("follower_id", followerID.String()),
-			zap.String("following_id", followingID.String()),
-		)
-		return fmt.Errorf("user already following")
-	}
-
-	if !errors.Is(err, gorm.ErrRecordNotFound) {
-		r.logger.Error("Failed to check for existing follow relationship",
-			zap.Error(err),
+// Follow creates the edge and reports whether it is new. An existing live
+// edge is not an error here; callers decide how to surface it.
+func (r *relationRepository) Follow(ctx context.Context, followerID, followingID uuid.UUID) (bool, error) {
+	var created bool
+	err := r.db.GetInstance().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		created, err = insertFollow(tx, followerID, followingID)
+		return err
+	})
+	if errors.Is(err, ErrFollowBlocked) {
+		r.logger.Warn("Attempted to follow across a block",
 			zap.String("follower_id", followerID.String()),
 			zap.String("following_id", followingID.String()),
 		)
-		return err
+		return false, err
 	}
-
-	follow := entities.Follows{
-		ID:          uuid.New(),
-		FollowerID:  followerID,
-		FollowingID: followingID,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
-	}
-
-	result := r.db.GetInstance().WithContext(ctx).Create(&follow)
-	if result.Error != nil {
+	if err != nil {
 		r.logger.Error("Failed to create follow relationship in database",
-			zap.Error(result.Error),
+			zap.Error(err),
 			zap.String("follower_id", followerID.String()),
 			zap.String("following_id", followingID.String()),
 		)
-		return result.Error
+		return false, err
 	}
 
-	cacheKey := fmt.Sprintf("follow:%s:%s", followerID.String(), followingID.String()) // Assuming a key generation function
+	cacheKey := followKey(followerID, followingID)
 	if err := r.redisCache.Set(ctx, cacheKey, "1", 10*time.Minute).Err(); err != nil {
 		r.logger.Error("Failed to set follow relationship in Redis cache",
 			zap.Error(err),
@@ -95,11 +113,93 @@ func (r *relationRepository) Follow(ctx context.Context, followerID, followingID
 		)
 	}
 
+	if !created {
+		r.logger.Info("Follow relationship already exists",
+			zap.String("follower_id", followerID.String()),
+			zap.String("following_id", followingID.String()),
+		)
+		return false, nil
+	}
+	invalidateStats(ctx, r.redisCache, r.logger, followerID, followingID)
+
 	r.logger.Info("User followed successfully",
 		zap.String("follower_id", followerID.String()),
 		zap.String("following_id", followingID.String()),
 	)
-	return nil
+	return true, nil
+}
+
+// ActivePairStatements build idx_follows_active_pair, which insertFollow and
+// BulkFollow rely on for ON CONFLICT. Duplicate live edges left from before
+// the index existed are soft-deleted first, keeping the oldest of each pair;
+// the stats reconciler then corrects the counters they inflated. Run them
+// in one transaction after AutoMigrate.
+var ActivePairStatements = []string{
+	`UPDATE follows SET deleted_at = NOW(), updated_at = NOW()
+	WHERE id IN (
+		SELECT id FROM (
+			SELECT id, ROW_NUMBER() OVER (PARTITION BY follower_id, following_id ORDER BY created_at, id) AS n
+			FROM follows
+			WHERE deleted_at IS NULL
+		) ranked
+		WHERE n > 1
+	)`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_follows_active_pair ON follows (follower_id, following_id) WHERE deleted_at IS NULL`,
+}
+
+// insertFollow writes the edge unless a live one already exists. The
+// conflict is resolved by idx_follows_active_pair rather than a prior
+// SELECT, so two concurrent follows cannot both insert; counters and the
+// outbox only move when a row was written. The block check is part of the
+// same statement, as in BulkFollow, and fails with ErrFollowBlocked; the
+// pair lock keeps a concurrent Block from missing the new edge.
+func insertFollow(tx *gorm.DB, followerID, followingID uuid.UUID) (bool, error) {
+	if err := lockPairs(tx, followerID, followingID); err != nil {
+		return false, err
+	}
+
+	followID := uuid.New()
+	now := time.Now()
+	result := tx.Exec(`
+		INSERT INTO follows (id, follower_id, following_id, created_at, updated_at)
+		SELECT @id, @follower, @following, @now, @now
+		WHERE NOT EXISTS (
+			SELECT 1 FROM blocks b
+			WHERE (b.blocker_id = @follower AND b.blocked_id = @following)
+				OR (b.blocker_id = @following AND b.blocked_id = @follower)
+		)
+		ON CONFLICT (follower_id, following_id) WHERE deleted_at IS NULL DO NOTHING`,
+		map[string]interface{}{"id": followID, "follower": followerID, "following": followingID, "now": now},
+	)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		// Nothing was written: tell a block apart from an existing edge.
+		var blocks int64
+		err := tx.Model(&entities.Blocks{}).
+			Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)",
+				followerID, followingID, followingID, followerID).
+			Count(&blocks).Error
+		if err != nil {
+			return false, err
+		}
+		if blocks > 0 {
+			return false, ErrFollowBlocked
+		}
+		return false, nil
+	}
+
+	if err := adjustFollowCounts(tx, followerID, followingID, 1); err != nil {
+		return false, err
+	}
+	return true, enqueueFollowEvents(tx, followEvent{
+		Type:        events.FollowCreated,
+		FollowID:    followID,
+		FollowerID:  followerID,
+		FollowingID: followingID,
+		OccurredAt:  now,
+	})
 }
 
 
@@ -109,27 +209,56 @@ func (r *relationRepository) Unfollow(ctx context.Context, followerID, following
 		zap.String("following_id", followingID.String()),
 	)
 
-	result := r.db.GetInstance().
-		Unscoped().
-		WithContext(ctx).
-		Where("follower_id = ? AND following_id = ?", followerID, followingID).
-		Delete(&entities.Follows{})
+	var rowsAffected int64
+	err := r.db.GetInstance().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		var removed []uuid.UUID
+		err := tx.Raw(`
+			UPDATE follows SET deleted_at = @now
+			WHERE follower_id = @follower AND following_id = @following AND deleted_at IS NULL
+			RETURNING id`,
+			map[string]interface{}{"follower": followerID, "following": followingID, "now": now},
+		).Scan(&removed).Error
+		if err != nil {
+			return err
+		}
 
-	if result.Error != nil {
+		rowsAffected = int64(len(removed))
+		if rowsAffected == 0 {
+			return nil
+		}
+		if err := adjustFollowCounts(tx, followerID, followingID, -1); err != nil {
+			return err
+		}
+
+		changes := make([]followEvent, 0, len(removed))
+		for _, followID := range removed {
+			changes = append(changes, followEvent{
+				Type:        events.FollowDeleted,
+				FollowID:    followID,
+				FollowerID:  followerID,
+				FollowingID: followingID,
+				OccurredAt:  now,
+			})
+		}
+		return enqueueFollowEvents(tx, changes...)
+	})
+
+	if err != nil {
 		r.logger.Error("Failed to delete follow relationship from database",
-			zap.Error(result.Error),
+			zap.Error(err),
 			zap.String("follower_id", followerID.String()),
 			zap.String("following_id", followingID.String()),
 		)
-		return result.Error
+		return err
 	}
 
-	if result.RowsAffected == 0 {
+	if rowsAffected == 0 {
 		r.logger.Warn("Unfollow attempt on a non-existent relationship",
 			zap.String("follower_id", followerID.String()),
 			zap.String("following_id", followingID.String()),
 		)
-		return fmt.Errorf("follow relationship not found")
+		return ErrNotFollowing
 	}
 
 	cacheKey := followKey(followerID, followingID)
@@ -139,6 +268,7 @@ func (r *relationRepository) Unfollow(ctx context.Context, followerID, following
 			zap.String("cache_key", cacheKey),
 		)
 	}
+	invalidateStats(ctx, r.redisCache, r.logger, followerID, followingID)
 
 	r.logger.Info("User unfollowed successfully",
 		zap.String("follower_id", followerID.String()),
@@ -204,49 +334,79 @@ func (r *relationRepository) IsFollowing(ctx context.Context, followerID, follow
 	return true, nil
 }
 
-func (r *relationRepository) GetFollowers(ctx context.Context, userID string, limit, offset int, nameFilter string) ([]responses.FollowerWithUserInfo, error) {
-	var followers []responses.FollowerWithUserInfo
-
+func (r *relationRepository) followersQuery(ctx context.Context, userID uuid.UUID, nameFilter string) *gorm.DB {
 	db := r.db.GetInstance().WithContext(ctx).
 		Table("follows").
-		Select("follows.id, follows.follower_id, users.name, users.username").
-		Joins("JOIN users ON follows.follower_id = users.id").
-		Where("follows.following_id = ?", userID).
-		Order("follows.created_at DESC").
-		Limit(limit).
-		Offset(offset)
+		Select("follows.id, follows.follower_id, users.name, users.username, follows.created_at").
+		Joins("JOIN users ON follows.follower_id = users.id AND users.deactivated_at IS NULL").
+		Where("follows.following_id = ? AND follows.deleted_at IS NULL", userID).
+		Where("NOT EXISTS (SELECT 1 FROM blocks b WHERE b.blocker_id = ? AND b.blocked_id = follows.follower_id)", userID)
 
 	if nameFilter != "" {
-		db = db.Where("LOWER(users.name) LIKE ?", "%"+strings.ToLower(nameFilter)+"%")
+		clause, args := util.NameMatch("users", nameFilter)
+		db = db.Where(clause, args...)
 	}
 
-	err := db.Find(&followers).Error
+	return db
+}
+
+func (r *relationRepository) GetFollowers(ctx context.Context, userID uuid.UUID, limit, offset int, nameFilter string) ([]responses.FollowerWithUserInfo, error) {
+	var followers []responses.FollowerWithUserInfo
+
+	err := r.followersQuery(ctx, userID, nameFilter).
+		Order("follows.created_at DESC, follows.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&followers).Error
 	return followers, err
 }
 
-func (r *relationRepository) GetFollowings(ctx context.Context, userID string, limit, offset int, nameFilter string) ([]responses.FollowingWithUserInfo, error) {
-	var results []responses.FollowingWithUserInfo
+// GetFollowersByCursor pages with a keyset on (created_at, id) instead of
+// OFFSET, so rows inserted while a client scrolls are neither skipped nor
+// repeated. A nil cursor starts from the newest follower.
+func (r *relationRepository) GetFollowersByCursor(ctx context.Context, userID uuid.UUID, limit int, after *util.Cursor, nameFilter string) ([]responses.FollowerWithUserInfo, error) {
+	var followers []responses.FollowerWithUserInfo
+
+	db := r.followersQuery(ctx, userID, nameFilter)
+	if after != nil {
+		db = db.Where("(follows.created_at, follows.id) < (?, ?)", after.CreatedAt, after.ID)
+	}
 
-	db := r.db.GetInstance().WithContext(ctx)
+	err := db.
+		Order("follows.created_at DESC, follows.id DESC").
+		Limit(limit).
+		Find(&followers).Error
+	return followers, err
+}
 
-	query := db.
+func (r *relationRepository) followingsQuery(ctx context.Context, userID uuid.UUID, nameFilter string) *gorm.DB {
+	query := r.db.GetInstance().WithContext(ctx).
 		Table("follows AS f").
 		Select(`
 			f.id,
 			f.follower_id,
 			f.following_id,
 			u.name,
-			u.username
+			u.username,
+			f.created_at
 		`).
-		Joins("JOIN users u ON f.following_id = u.id").
-		Where("f.follower_id = ?", userID)
+		Joins("JOIN users u ON f.following_id = u.id AND u.deactivated_at IS NULL").
+		Where("f.follower_id = ? AND f.deleted_at IS NULL", userID).
+		Where("NOT EXISTS (SELECT 1 FROM blocks b WHERE b.blocker_id = ? AND b.blocked_id = f.following_id)", userID)
 
 	if nameFilter != "" {
-		query = query.Where("(u.name ILIKE ? OR u.username ILIKE ?)", "%"+nameFilter+"%", "%"+nameFilter+"%")
+		clause, args := util.NameMatch("u", nameFilter)
+		query = query.Where(clause, args...)
 	}
 
-	err := query.
-		Order("f.created_at DESC").
+	return query
+}
+
+func (r *relationRepository) GetFollowings(ctx context.Context, userID uuid.UUID, limit, offset int, nameFilter string) ([]responses.FollowingWithUserInfo, error) {
+	var results []responses.FollowingWithUserInfo
+
+	err := r.followingsQuery(ctx, userID, nameFilter).
+		Order("f.created_at DESC, f.id DESC").
 		Limit(limit).
 		Offset(offset).
 		Scan(&results).Error
@@ -258,3 +418,46 @@ func (r *relationRepository) GetFollowings(ctx context.Context, userID string, l
 	return results, nil
 }
 
+// GetFollowingsByCursor is the keyset counterpart of GetFollowings.
+func (r *relationRepository) GetFollowingsByCursor(ctx context.Context, userID uuid.UUID, limit int, after *util.Cursor, nameFilter string) ([]responses.FollowingWithUserInfo, error) {
+	var results []responses.FollowingWithUserInfo
+
+	query := r.followingsQuery(ctx, userID, nameFilter)
+	if after != nil {
+		query = query.Where("(f.created_at, f.id) < (?, ?)", after.CreatedAt, after.ID)
+	}
+
+	err := query.
+		Order("f.created_at DESC, f.id DESC").
+		Limit(limit).
+		Scan(&results).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (r *relationRepository) GetMutuals(ctx context.Context, userID uuid.UUID, limit, offset int, nameFilter string) ([]responses.FollowerWithUserInfo, error) {
+	var mutuals []responses.FollowerWithUserInfo
+
+	db := r.db.GetInstance().WithContext(ctx).
+		Table("follows").
+		Select("follows.id, follows.follower_id, users.name, users.username").
+		Joins("JOIN users ON follows.follower_id = users.id AND users.deactivated_at IS NULL").
+		Joins("JOIN follows back ON back.follower_id = follows.following_id AND back.following_id = follows.follower_id AND back.deleted_at IS NULL").
+		Where("follows.following_id = ? AND follows.deleted_at IS NULL", userID).
+		Where("NOT EXISTS (SELECT 1 FROM blocks b WHERE b.blocker_id = ? AND b.blocked_id = follows.follower_id)", userID).
+		Order("follows.created_at DESC").
+		Limit(limit).
+		Offset(offset)
+
+	if nameFilter != "" {
+		clause, args := util.NameMatch("users", nameFilter)
+		db = db.Where(clause, args...)
+	}
+
+	err := db.Find(&mutuals).Error
+	return mutuals, err
+}
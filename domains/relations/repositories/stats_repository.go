@@ -0,0 +1,215 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/relations/entities"
+	"github.com/malikhisyam/user-graph-service/infrastructures"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type StatsRepository interface {
+	GetStats(ctx context.Context, userID uuid.UUID) (*entities.UserStats, error)
+	Reconcile(ctx context.Context) ([]uuid.UUID, error)
+}
+
+type statsRepository struct {
+	db         infrastructures.Database
+	redisCache *redis.Client
+	logger     util.Logger
+}
+
+func NewStatsRepository(db infrastructures.Database, redisClient *redis.Client, logger util.Logger) StatsRepository {
+	return &statsRepository{
+		db:         db,
+		redisCache: redisClient,
+		logger:     logger,
+	}
+}
+
+func statsKey(userID uuid.UUID) string {
+	return fmt.Sprintf("stats:%s", userID.String())
+}
+
+// The first write for a user seeds the row from the follows table, later
+// writes only apply the delta. The statements run inside the transaction that
+// changed the edge, so the seeded count already includes that change.
+const (
+	upsertFollowersCountSQL = `
+		INSERT INTO user_stats (user_id, followers_count, followings_count, updated_at)
+		VALUES (
+			@user,
+			(SELECT COUNT(*) FROM follows WHERE following_id = @user AND deleted_at IS NULL),
+			(SELECT COUNT(*) FROM follows WHERE follower_id = @user AND deleted_at IS NULL),
+			NOW()
+		)
+		ON CONFLICT (user_id) DO UPDATE SET
+			followers_count = GREATEST(user_stats.followers_count + @delta, 0),
+			updated_at = NOW()`
+
+	upsertFollowingsCountSQL = `
+		INSERT INTO user_stats (user_id, followers_count, followings_count, updated_at)
+		VALUES (
+			@user,
+			(SELECT COUNT(*) FROM follows WHERE following_id = @user AND deleted_at IS NULL),
+			(SELECT COUNT(*) FROM follows WHERE follower_id = @user AND deleted_at IS NULL),
+			NOW()
+		)
+		ON CONFLICT (user_id) DO UPDATE SET
+			followings_count = GREATEST(user_stats.followings_count + @delta, 0),
+			updated_at = NOW()`
+)
+
+// adjustFollowCounts applies delta to the follower's following total and the
+// followed user's follower total using the caller's transaction.
+func adjustFollowCounts(tx *gorm.DB, followerID, followingID uuid.UUID, delta int) error {
+	err := tx.Exec(upsertFollowingsCountSQL, map[string]interface{}{"user": followerID, "delta": delta}).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Exec(upsertFollowersCountSQL, map[string]interface{}{"user": followingID, "delta": delta}).Error
+}
+
+func invalidateStats(ctx context.Context, redisCache *redis.Client, logger util.Logger, userIDs ...uuid.UUID) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		keys = append(keys, statsKey(id))
+	}
+
+	if err := redisCache.Del(ctx, keys...).Err(); err != nil {
+		logger.Error("Failed to invalidate stats in Redis cache",
+			zap.Error(err),
+			zap.Strings("cache_keys", keys),
+		)
+	}
+}
+
+func (r *statsRepository) GetStats(ctx context.Context, userID uuid.UUID) (*entities.UserStats, error) {
+	cacheKey := statsKey(userID)
+
+	cached, err := r.redisCache.HGetAll(ctx, cacheKey).Result()
+	if err != nil {
+		r.logger.Error("Redis error during GetStats",
+			zap.Error(err),
+			zap.String("cache_key", cacheKey),
+		)
+	} else if stats, ok := parseCachedStats(userID, cached); ok {
+		r.logger.Debug("Cache hit for GetStats", zap.String("cache_key", cacheKey))
+		return stats, nil
+	}
+
+	var stats entities.UserStats
+	dbErr := r.db.GetInstance().
+		WithContext(ctx).
+		Where("user_id = ?", userID).
+		First(&stats).Error
+
+	if errors.Is(dbErr, gorm.ErrRecordNotFound) {
+		// No edge has touched this user since counters were introduced,
+		// seed the row once instead of counting on every request.
+		dbErr = r.db.GetInstance().WithContext(ctx).Raw(`
+			INSERT INTO user_stats (user_id, followers_count, followings_count, updated_at)
+			VALUES (
+				@user,
+				(SELECT COUNT(*) FROM follows WHERE following_id = @user AND deleted_at IS NULL),
+				(SELECT COUNT(*) FROM follows WHERE follower_id = @user AND deleted_at IS NULL),
+				NOW()
+			)
+			ON CONFLICT (user_id) DO UPDATE SET updated_at = user_stats.updated_at
+			RETURNING user_id, followers_count, followings_count, updated_at`,
+			map[string]interface{}{"user": userID},
+		).Scan(&stats).Error
+	}
+
+	if dbErr != nil {
+		r.logger.Error("Database error during GetStats",
+			zap.Error(dbErr),
+			zap.String("user_id", userID.String()),
+		)
+		return nil, dbErr
+	}
+
+	pipe := r.redisCache.TxPipeline()
+	pipe.HSet(ctx, cacheKey,
+		"followers", stats.FollowersCount,
+		"followings", stats.FollowingsCount,
+	)
+	pipe.Expire(ctx, cacheKey, 10*time.Minute)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("Failed to set stats in Redis cache",
+			zap.Error(err),
+			zap.String("cache_key", cacheKey),
+		)
+	}
+
+	return &stats, nil
+}
+
+// Reconcile recomputes every user's totals from the follows table and
+// rewrites the rows that drifted. A follow landing while the statement runs
+// may be overwritten with a stale count; the next run repairs it.
+func (r *statsRepository) Reconcile(ctx context.Context) ([]uuid.UUID, error) {
+	var repaired []uuid.UUID
+
+	err := r.db.GetInstance().WithContext(ctx).Raw(`
+		INSERT INTO user_stats (user_id, followers_count, followings_count, updated_at)
+		SELECT
+			u.id,
+			COALESCE(fr.total, 0),
+			COALESCE(fg.total, 0),
+			NOW()
+		FROM users u
+		LEFT JOIN (
+			SELECT following_id, COUNT(*) AS total FROM follows WHERE deleted_at IS NULL GROUP BY following_id
+		) fr ON fr.following_id = u.id
+		LEFT JOIN (
+			SELECT follower_id, COUNT(*) AS total FROM follows WHERE deleted_at IS NULL GROUP BY follower_id
+		) fg ON fg.follower_id = u.id
+		ON CONFLICT (user_id) DO UPDATE SET
+			followers_count = EXCLUDED.followers_count,
+			followings_count = EXCLUDED.followings_count,
+			updated_at = NOW()
+		WHERE user_stats.followers_count <> EXCLUDED.followers_count
+			OR user_stats.followings_count <> EXCLUDED.followings_count
+		RETURNING user_id`,
+	).Scan(&repaired).Error
+	if err != nil {
+		r.logger.Error("Failed to reconcile user stats", zap.Error(err))
+		return nil, err
+	}
+
+	invalidateStats(ctx, r.redisCache, r.logger, repaired...)
+
+	return repaired, nil
+}
+
+func parseCachedStats(userID uuid.UUID, cached map[string]string) (*entities.UserStats, bool) {
+	followers, err := strconv.ParseInt(cached["followers"], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	followings, err := strconv.ParseInt(cached["followings"], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	return &entities.UserStats{
+		UserID:          userID,
+		FollowersCount:  followers,
+		FollowingsCount: followings,
+	}, true
+}
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/relations/models/responses"
+	"go.uber.org/zap"
+)
+
+const suggestionSampleSize = 3
+
+type followedBySample struct {
+	FollowingID string
+	Name        string
+}
+
+// GetSuggestions ranks accounts followed by the people userID follows. Each
+// candidate is scored by the number of distinct paths reaching it, so an
+// account followed by five of your followings outranks one followed by two.
+// Paths through deactivated followings count for nothing, as they are never
+// named in the followed-by sample either.
+func (r *relationRepository) GetSuggestions(ctx context.Context, userID uuid.UUID, limit int) ([]responses.SuggestionWithUserInfo, error) {
+	var suggestions []responses.SuggestionWithUserInfo
+
+	err := r.db.GetInstance().WithContext(ctx).Raw(`
+		SELECT
+			f2.following_id,
+			u.name,
+			u.username,
+			u.profile AS avatar_url,
+			COUNT(*) AS score
+		FROM follows f1
+		JOIN follows f2 ON f2.follower_id = f1.following_id AND f2.deleted_at IS NULL
+		JOIN users via ON via.id = f1.following_id AND via.deactivated_at IS NULL
+		JOIN users u ON u.id = f2.following_id AND u.deactivated_at IS NULL
+		WHERE f1.follower_id = @user
+			AND f1.deleted_at IS NULL
+			AND f2.following_id <> @user
+			AND NOT EXISTS (
+				SELECT 1 FROM follows mine
+				WHERE mine.follower_id = @user AND mine.following_id = f2.following_id AND mine.deleted_at IS NULL
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM follow_requests fr
+				WHERE fr.requester_id = @user AND fr.target_id = f2.following_id
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM blocks b
+				WHERE (b.blocker_id = @user AND b.blocked_id = f2.following_id)
+					OR (b.blocker_id = f2.following_id AND b.blocked_id = @user)
+			)
+		GROUP BY f2.following_id, u.name, u.username, u.profile
+		ORDER BY score DESC, f2.following_id
+		LIMIT @limit`,
+		map[string]interface{}{"user": userID, "limit": limit},
+	).Scan(&suggestions).Error
+	if err != nil {
+		r.logger.Error("Failed to compute follow suggestions",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+		)
+		return nil, err
+	}
+
+	if len(suggestions) == 0 {
+		return suggestions, nil
+	}
+
+	candidateIDs := make([]string, 0, len(suggestions))
+	for _, s := range suggestions {
+		candidateIDs = append(candidateIDs, s.FollowingID)
+	}
+
+	var samples []followedBySample
+	err = r.db.GetInstance().WithContext(ctx).Raw(`
+		SELECT s.following_id, s.name
+		FROM (
+			SELECT
+				f2.following_id,
+				u.name,
+				ROW_NUMBER() OVER (PARTITION BY f2.following_id ORDER BY f1.created_at DESC) AS rn
+			FROM follows f1
+			JOIN follows f2 ON f2.follower_id = f1.following_id AND f2.deleted_at IS NULL
+			JOIN users u ON u.id = f1.following_id AND u.deactivated_at IS NULL
+			WHERE f1.follower_id = @user AND f1.deleted_at IS NULL AND f2.following_id IN @candidates
+		) s
+		WHERE s.rn <= @sample`,
+		map[string]interface{}{"user": userID, "candidates": candidateIDs, "sample": suggestionSampleSize},
+	).Scan(&samples).Error
+	if err != nil {
+		r.logger.Error("Failed to load followed-by samples for suggestions",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+		)
+		return nil, err
+	}
+
+	followedBy := make(map[string][]string, len(suggestions))
+	for _, s := range samples {
+		followedBy[s.FollowingID] = append(followedBy[s.FollowingID], s.Name)
+	}
+	for i := range suggestions {
+		suggestions[i].FollowedBy = followedBy[suggestions[i].FollowingID]
+	}
+
+	return suggestions, nil
+}
@@ -0,0 +1,136 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/relations/models/responses"
+)
+
+// BulkFollowStatus is the outcome of one target of a bulk follow.
+type BulkFollowStatus string
+
+const (
+	BulkFollowStatusFollowed         BulkFollowStatus = "followed"
+	BulkFollowStatusAlreadyFollowing BulkFollowStatus = "already_following"
+	BulkFollowStatusRequested        BulkFollowStatus = "requested"
+	BulkFollowStatusNotFound         BulkFollowStatus = "not_found"
+	BulkFollowStatusBlocked          BulkFollowStatus = "blocked"
+	BulkFollowStatusSelf             BulkFollowStatus = "cannot_follow_self"
+)
+
+// BulkFollowResult reports one input target, given either by id or by
+// username, in the order it was submitted.
+type BulkFollowResult struct {
+	Target string           `json:"target"`
+	UserID *uuid.UUID       `json:"user_id,omitempty"`
+	Status BulkFollowStatus `json:"status"`
+}
+
+// BulkFollow applies the Follow rules to every target at once: public
+// accounts are followed in a single insert, private ones get a follow
+// request, and self, blocked, unknown or already followed targets are
+// reported without failing the batch.
+func (u *relationUsecase) BulkFollow(ctx context.Context, followerID uuid.UUID, targetIDs []uuid.UUID, usernames []string) ([]BulkFollowResult, error) {
+	if err := u.ensureActive(ctx, followerID); err != nil {
+		return nil, err
+	}
+
+	targets, err := u.relationRepo.ResolveBulkTargets(ctx, followerID, targetIDs, usernames)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]responses.BulkFollowTarget, len(targets))
+	byUsername := make(map[string]responses.BulkFollowTarget, len(targets))
+	for _, target := range targets {
+		byID[target.ID] = target
+		byUsername[strings.ToLower(target.Username)] = target
+	}
+
+	results := make([]BulkFollowResult, 0, len(targetIDs)+len(usernames))
+	for _, id := range targetIDs {
+		target, ok := byID[id]
+		results = append(results, bulkResult(id.String(), target, ok))
+	}
+	for _, username := range usernames {
+		target, ok := byUsername[strings.ToLower(strings.TrimSpace(username))]
+		results = append(results, bulkResult(username, target, ok))
+	}
+
+	var toFollow, toRequest []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for i, result := range results {
+		if result.UserID == nil {
+			continue
+		}
+		id := *result.UserID
+		if id == followerID {
+			results[i].Status = BulkFollowStatusSelf
+			continue
+		}
+		if result.Status != "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if byID[id].IsPrivate {
+			toRequest = append(toRequest, id)
+		} else {
+			toFollow = append(toFollow, id)
+		}
+	}
+
+	if err := u.relationRepo.BulkCreateFollowRequests(ctx, followerID, toRequest); err != nil {
+		return nil, err
+	}
+
+	created, err := u.relationRepo.BulkFollow(ctx, followerID, toFollow)
+	if err != nil {
+		return nil, err
+	}
+
+	followed := make(map[uuid.UUID]bool, len(created))
+	for _, followingID := range created {
+		followed[followingID] = true
+		u.timelineUc.OnFollow(ctx, followerID, followingID)
+	}
+
+	for i, result := range results {
+		if result.UserID == nil || result.Status != "" {
+			continue
+		}
+		switch id := *result.UserID; {
+		case byID[id].IsPrivate:
+			results[i].Status = BulkFollowStatusRequested
+		case followed[id]:
+			results[i].Status = BulkFollowStatusFollowed
+		default:
+			// Followed or blocked concurrently since the targets were resolved.
+			results[i].Status = BulkFollowStatusAlreadyFollowing
+		}
+	}
+
+	return results, nil
+}
+
+// bulkResult fills in the statuses known before anything is written; targets
+// still to be followed or requested are left with an empty status.
+func bulkResult(input string, target responses.BulkFollowTarget, found bool) BulkFollowResult {
+	result := BulkFollowResult{Target: input}
+	if !found {
+		result.Status = BulkFollowStatusNotFound
+		return result
+	}
+
+	id := target.ID
+	result.UserID = &id
+	switch {
+	case target.Blocked:
+		result.Status = BulkFollowStatusBlocked
+	case target.AlreadyFollowing:
+		result.Status = BulkFollowStatusAlreadyFollowing
+	}
+	return result
+}
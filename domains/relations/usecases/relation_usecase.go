@@ -2,42 +2,117 @@ package usecases
 
 import (
 	"context"
-	"errors"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/relations/entities"
 	"github.com/malikhisyam/user-graph-service/domains/relations/models/responses"
 	"github.com/malikhisyam/user-graph-service/domains/relations/repositories"
+	timelineUc "github.com/malikhisyam/user-graph-service/domains/timeline/usecases"
+	userRepo "github.com/malikhisyam/user-graph-service/domains/users/repositories"
+	"github.com/malikhisyam/user-graph-service/shared/apperror"
+	"github.com/malikhisyam/user-graph-service/shared/events"
+	"github.com/malikhisyam/user-graph-service/shared/util"
 )
 
 var (
-	ErrCannotFollowSelf   = errors.New("cannot follow yourself")
-	ErrCannotUnfollowSelf = errors.New("cannot unfollow yourself")
+	ErrCannotFollowSelf   = apperror.Unprocessable("cannot_follow_self", "cannot follow yourself")
+	ErrCannotUnfollowSelf = apperror.Unprocessable("cannot_unfollow_self", "cannot unfollow yourself")
+	ErrCannotBlockSelf    = apperror.Unprocessable("cannot_block_self", "cannot block yourself")
+	ErrCannotUnblockSelf  = apperror.Unprocessable("cannot_unblock_self", "cannot unblock yourself")
+	ErrCannotRemoveSelf   = apperror.Unprocessable("cannot_remove_self", "cannot remove yourself as a follower")
+)
+
+// purgeBatchSize bounds the rows removed per transaction by PurgeUser.
+const purgeBatchSize = 1000
+
+// FollowStatus describes what a follow attempt resulted in.
+type FollowStatus string
+
+const (
+	FollowStatusFollowed  FollowStatus = "followed"
+	FollowStatusRequested FollowStatus = "requested"
 )
 
 type RelationUseCase interface {
-	Follow(ctx context.Context, followerID, followingID uuid.UUID) error
+	Follow(ctx context.Context, followerID, followingID uuid.UUID) (FollowStatus, error)
+	BulkFollow(ctx context.Context, followerID uuid.UUID, targetIDs []uuid.UUID, usernames []string) ([]BulkFollowResult, error)
 	Unfollow(ctx context.Context, followerID, followingID uuid.UUID) error
+	RemoveFollower(ctx context.Context, userID, followerID uuid.UUID) error
 	IsFollowing(ctx context.Context, followerID, followingID uuid.UUID) (bool, error)
-	GetFollowers(ctx context.Context, userID string, limit, offset int, nameFilter string) ([]responses.FollowerWithUserInfo, error) 
-	GetFollowings(ctx context.Context, userID string, limit, offset int, nameFilter string) ([]responses.FollowingWithUserInfo, error)
+	GetFollowers(ctx context.Context, userID uuid.UUID, limit, offset int, nameFilter string) ([]responses.FollowerWithUserInfo, error) 
+	GetFollowings(ctx context.Context, userID uuid.UUID, limit, offset int, nameFilter string) ([]responses.FollowingWithUserInfo, error)
+	GetFollowersByCursor(ctx context.Context, userID uuid.UUID, limit int, after *util.Cursor, nameFilter string) ([]responses.FollowerWithUserInfo, *util.Cursor, error)
+	GetFollowingsByCursor(ctx context.Context, userID uuid.UUID, limit int, after *util.Cursor, nameFilter string) ([]responses.FollowingWithUserInfo, *util.Cursor, error)
+	GetMutuals(ctx context.Context, userID uuid.UUID, limit, offset int, nameFilter string) ([]responses.FollowerWithUserInfo, error)
+	GetSuggestions(ctx context.Context, userID uuid.UUID, limit int) ([]responses.SuggestionWithUserInfo, error)
+	GetHistory(ctx context.Context, userID uuid.UUID, limit, offset int) ([]responses.FollowEventWithUserInfo, error)
+	Block(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	GetIncomingFollowRequests(ctx context.Context, userID uuid.UUID, limit, offset int) ([]responses.FollowRequestWithUserInfo, error)
+	GetOutgoingFollowRequests(ctx context.Context, userID uuid.UUID, limit, offset int) ([]responses.FollowRequestWithUserInfo, error)
+	GetFollowRequest(ctx context.Context, requestID uuid.UUID) (*entities.FollowRequests, error)
+	ApproveFollowRequest(ctx context.Context, requestID uuid.UUID) error
+	RejectFollowRequest(ctx context.Context, requestID uuid.UUID) error
+	CancelFollowRequest(ctx context.Context, requestID uuid.UUID) error
+	GetStats(ctx context.Context, userID uuid.UUID) (*entities.UserStats, error)
+	ReconcileStats(ctx context.Context) (int, error)
+	PurgeUser(ctx context.Context, userID uuid.UUID) error
+	RelayOutbox(ctx context.Context, batchSize int, parkAfter time.Duration) (int, error)
+	ListParkedOutbox(ctx context.Context, limit, offset int) ([]entities.Outbox, error)
+	ReplayParkedOutbox(ctx context.Context, eventID uuid.UUID) (int, error)
+	PruneOutbox(ctx context.Context, publishedBefore time.Time) (int, error)
 }
 
 type relationUsecase struct {
 	relationRepo repositories.RelationRepository
+	statsRepo    repositories.StatsRepository
+	userRepo     userRepo.UserRepository
+	timelineUc   timelineUc.TimelineUseCase
+	publisher    events.Publisher
 }
 
-func NewRelationUseCase(relationRepo repositories.RelationRepository) RelationUseCase {
+func NewRelationUseCase(relationRepo repositories.RelationRepository, statsRepo repositories.StatsRepository, userRepo userRepo.UserRepository, timelineUc timelineUc.TimelineUseCase, publisher events.Publisher) RelationUseCase {
 	return &relationUsecase{
 		relationRepo: relationRepo,
+		statsRepo:    statsRepo,
+		userRepo:     userRepo,
+		timelineUc:   timelineUc,
+		publisher:    publisher,
 	}
 }
 
-func (u *relationUsecase) Follow(ctx context.Context, followerID, followingID uuid.UUID) error {
+func (u *relationUsecase) Follow(ctx context.Context, followerID, followingID uuid.UUID) (FollowStatus, error) {
 	if followerID == followingID {
-		return ErrCannotFollowSelf
+		return "", ErrCannotFollowSelf
+	}
+
+	if err := u.ensureActive(ctx, followerID, followingID); err != nil {
+		return "", err
+	}
+
+	isPrivate, err := u.relationRepo.IsPrivateAccount(ctx, followingID)
+	if err != nil {
+		return "", err
+	}
+
+	if isPrivate {
+		if err := u.relationRepo.CreateFollowRequest(ctx, followerID, followingID); err != nil {
+			return "", err
+		}
+		return FollowStatusRequested, nil
+	}
+
+	created, err := u.relationRepo.Follow(ctx, followerID, followingID)
+	if err != nil {
+		return "", err
 	}
+	if !created {
+		return "", repositories.ErrAlreadyFollowing
+	}
+	u.timelineUc.OnFollow(ctx, followerID, followingID)
 
-	return u.relationRepo.Follow(ctx, followerID, followingID)
+	return FollowStatusFollowed, nil
 }
 
 func (u *relationUsecase) Unfollow(ctx context.Context, followerID, followingID uuid.UUID) error {
@@ -45,22 +120,243 @@ func (u *relationUsecase) Unfollow(ctx context.Context, followerID, followingID
 		return ErrCannotUnfollowSelf
 	}
 
-	return u.relationRepo.Unfollow(ctx, followerID, followingID)
+	if err := u.relationRepo.Unfollow(ctx, followerID, followingID); err != nil {
+		return err
+	}
+	u.timelineUc.OnUnfollow(ctx, followerID, followingID)
+
+	return nil
+}
+
+// RemoveFollower drops followerID from userID's followers without blocking
+// them; they may follow again later.
+func (u *relationUsecase) RemoveFollower(ctx context.Context, userID, followerID uuid.UUID) error {
+	if userID == followerID {
+		return ErrCannotRemoveSelf
+	}
+
+	if err := u.relationRepo.Unfollow(ctx, followerID, userID); err != nil {
+		return err
+	}
+	u.timelineUc.OnUnfollow(ctx, followerID, userID)
+
+	// Best effort; the follow is already gone.
+	_ = u.publisher.Publish(ctx, events.New(events.FollowerRemoved, map[string]interface{}{
+		"user_id":     userID.String(),
+		"follower_id": followerID.String(),
+	}))
+
+	return nil
 }
 
 func (u *relationUsecase) IsFollowing(ctx context.Context, followerID, followingID uuid.UUID) (bool, error) {
 	return u.relationRepo.IsFollowing(ctx, followerID, followingID)
 }
 
-func (u *relationUsecase) GetFollowers(ctx context.Context, userID string, limit, offset int, nameFilter string) ([]responses.FollowerWithUserInfo, error) {
+func (u *relationUsecase) GetFollowers(ctx context.Context, userID uuid.UUID, limit, offset int, nameFilter string) ([]responses.FollowerWithUserInfo, error) {
+	if err := u.ensureActive(ctx, userID); err != nil {
+		return nil, err
+	}
 	return u.relationRepo.GetFollowers(ctx, userID, limit, offset, nameFilter)
 }
 
 
 
-func (u *relationUsecase) GetFollowings(ctx context.Context, userID string, limit, offset int, nameFilter string) ([]responses.FollowingWithUserInfo, error) {
+func (u *relationUsecase) GetFollowings(ctx context.Context, userID uuid.UUID, limit, offset int, nameFilter string) ([]responses.FollowingWithUserInfo, error) {
+	if err := u.ensureActive(ctx, userID); err != nil {
+		return nil, err
+	}
 	return u.relationRepo.GetFollowings(ctx, userID, limit, offset, nameFilter)
 }
 
+// GetFollowersByCursor returns one page of followers and the cursor of the
+// next page, which is nil once the list is exhausted.
+func (u *relationUsecase) GetFollowersByCursor(ctx context.Context, userID uuid.UUID, limit int, after *util.Cursor, nameFilter string) ([]responses.FollowerWithUserInfo, *util.Cursor, error) {
+	if err := u.ensureActive(ctx, userID); err != nil {
+		return nil, nil, err
+	}
+
+	followers, err := u.relationRepo.GetFollowersByCursor(ctx, userID, limit+1, after, nameFilter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(followers) <= limit {
+		return followers, nil, nil
+	}
+
+	followers = followers[:limit]
+	last := followers[len(followers)-1]
+	return followers, &util.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}, nil
+}
+
+func (u *relationUsecase) GetFollowingsByCursor(ctx context.Context, userID uuid.UUID, limit int, after *util.Cursor, nameFilter string) ([]responses.FollowingWithUserInfo, *util.Cursor, error) {
+	if err := u.ensureActive(ctx, userID); err != nil {
+		return nil, nil, err
+	}
+
+	followings, err := u.relationRepo.GetFollowingsByCursor(ctx, userID, limit+1, after, nameFilter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(followings) <= limit {
+		return followings, nil, nil
+	}
+
+	followings = followings[:limit]
+	last := followings[len(followings)-1]
+	return followings, &util.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}, nil
+}
+
+func (u *relationUsecase) GetMutuals(ctx context.Context, userID uuid.UUID, limit, offset int, nameFilter string) ([]responses.FollowerWithUserInfo, error) {
+	if err := u.ensureActive(ctx, userID); err != nil {
+		return nil, err
+	}
+	return u.relationRepo.GetMutuals(ctx, userID, limit, offset, nameFilter)
+}
+
+func (u *relationUsecase) GetSuggestions(ctx context.Context, userID uuid.UUID, limit int) ([]responses.SuggestionWithUserInfo, error) {
+	if err := u.ensureActive(ctx, userID); err != nil {
+		return nil, err
+	}
+	return u.relationRepo.GetSuggestions(ctx, userID, limit)
+}
+
+func (u *relationUsecase) GetHistory(ctx context.Context, userID uuid.UUID, limit, offset int) ([]responses.FollowEventWithUserInfo, error) {
+	if err := u.ensureActive(ctx, userID); err != nil {
+		return nil, err
+	}
+	return u.relationRepo.GetHistory(ctx, userID, limit, offset)
+}
+
+func (u *relationUsecase) Block(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	if blockerID == blockedID {
+		return ErrCannotBlockSelf
+	}
+
+	if err := u.ensureActive(ctx, blockerID, blockedID); err != nil {
+		return err
+	}
+
+	if err := u.relationRepo.Block(ctx, blockerID, blockedID); err != nil {
+		return err
+	}
+	u.timelineUc.OnUnfollow(ctx, blockerID, blockedID)
+	u.timelineUc.OnUnfollow(ctx, blockedID, blockerID)
+
+	return nil
+}
+
+func (u *relationUsecase) Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	if blockerID == blockedID {
+		return ErrCannotUnblockSelf
+	}
+
+	return u.relationRepo.Unblock(ctx, blockerID, blockedID)
+}
+
+func (u *relationUsecase) GetIncomingFollowRequests(ctx context.Context, userID uuid.UUID, limit, offset int) ([]responses.FollowRequestWithUserInfo, error) {
+	return u.relationRepo.GetIncomingFollowRequests(ctx, userID, limit, offset)
+}
+
+func (u *relationUsecase) GetOutgoingFollowRequests(ctx context.Context, userID uuid.UUID, limit, offset int) ([]responses.FollowRequestWithUserInfo, error) {
+	return u.relationRepo.GetOutgoingFollowRequests(ctx, userID, limit, offset)
+}
+
+func (u *relationUsecase) GetFollowRequest(ctx context.Context, requestID uuid.UUID) (*entities.FollowRequests, error) {
+	return u.relationRepo.GetFollowRequest(ctx, requestID)
+}
+
+func (u *relationUsecase) ApproveFollowRequest(ctx context.Context, requestID uuid.UUID) error {
+	request, err := u.relationRepo.ApproveFollowRequest(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	u.timelineUc.OnFollow(ctx, request.RequesterID, request.TargetID)
 
+	return nil
+}
+
+func (u *relationUsecase) RejectFollowRequest(ctx context.Context, requestID uuid.UUID) error {
+	return u.relationRepo.DeleteFollowRequest(ctx, requestID)
+}
 
+func (u *relationUsecase) CancelFollowRequest(ctx context.Context, requestID uuid.UUID) error {
+	return u.relationRepo.DeleteFollowRequest(ctx, requestID)
+}
+
+func (u *relationUsecase) GetStats(ctx context.Context, userID uuid.UUID) (*entities.UserStats, error) {
+	// Checked first because reading stats seeds a row for the user.
+	if err := u.ensureActive(ctx, userID); err != nil {
+		return nil, err
+	}
+	return u.statsRepo.GetStats(ctx, userID)
+}
+
+func (u *relationUsecase) ReconcileStats(ctx context.Context) (int, error) {
+	repaired, err := u.statsRepo.Reconcile(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return len(repaired), nil
+}
+
+// PurgeUser deletes every edge, block and follow request of a user who is
+// being deleted, one bounded batch at a time.
+func (u *relationUsecase) PurgeUser(ctx context.Context, userID uuid.UUID) error {
+	for {
+		removed, err := u.relationRepo.PurgeUserBatch(ctx, userID, purgeBatchSize)
+		if err != nil {
+			return err
+		}
+		if removed == 0 {
+			return nil
+		}
+	}
+}
+
+// RelayOutbox publishes one batch of pending follow events and reports how
+// many went out.
+func (u *relationUsecase) RelayOutbox(ctx context.Context, batchSize int, parkAfter time.Duration) (int, error) {
+	return u.relationRepo.RelayOutbox(ctx, batchSize, parkAfter, u.publisher.Publish)
+}
+
+func (u *relationUsecase) ListParkedOutbox(ctx context.Context, limit, offset int) ([]entities.Outbox, error) {
+	return u.relationRepo.ListParkedOutbox(ctx, limit, offset)
+}
+
+// ReplayParkedOutbox releases the parked event eventID, or every parked
+// event when it is uuid.Nil, back to the relay.
+func (u *relationUsecase) ReplayParkedOutbox(ctx context.Context, eventID uuid.UUID) (int, error) {
+	return u.relationRepo.ReplayParkedOutbox(ctx, eventID)
+}
+
+// PruneOutbox deletes events published before publishedBefore, one bounded
+// batch at a time, and reports how many went.
+func (u *relationUsecase) PruneOutbox(ctx context.Context, publishedBefore time.Time) (int, error) {
+	total := 0
+	for {
+		removed, err := u.relationRepo.PruneOutbox(ctx, publishedBefore, purgeBatchSize)
+		if err != nil {
+			return total, err
+		}
+		total += removed
+		if removed < purgeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// ensureActive fails with ErrUserNotFound unless every user exists and is
+// not deactivated, so edges never point at missing accounts.
+func (u *relationUsecase) ensureActive(ctx context.Context, userIDs ...uuid.UUID) error {
+	active, err := u.userRepo.AreActive(ctx, userIDs...)
+	if err != nil {
+		return err
+	}
+	if !active {
+		return repositories.ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/relations/entities"
+	"github.com/malikhisyam/user-graph-service/domains/relations/repositories"
+	userRepo "github.com/malikhisyam/user-graph-service/domains/users/repositories"
+	"github.com/malikhisyam/user-graph-service/shared/apperror"
+)
+
+// knownUsers answers AreActive from a fixed set; the rest of the interface
+// is left unimplemented.
+type knownUsers struct {
+	userRepo.UserRepository
+	active map[uuid.UUID]bool
+}
+
+func (r *knownUsers) AreActive(ctx context.Context, userIDs ...uuid.UUID) (bool, error) {
+	for _, id := range userIDs {
+		if !r.active[id] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// recordingStats remembers which users had their stats read.
+type recordingStats struct {
+	repositories.StatsRepository
+	read []uuid.UUID
+}
+
+func (r *recordingStats) GetStats(ctx context.Context, userID uuid.UUID) (*entities.UserStats, error) {
+	r.read = append(r.read, userID)
+	return &entities.UserStats{UserID: userID}, nil
+}
+
+func TestGetStatsUnknownUser(t *testing.T) {
+	stats := &recordingStats{}
+	uc := NewRelationUseCase(nil, stats, &knownUsers{active: map[uuid.UUID]bool{}}, nil, nil)
+
+	_, err := uc.GetStats(context.Background(), uuid.New())
+
+	if !errors.Is(err, repositories.ErrUserNotFound) {
+		t.Fatalf("err = %v, want ErrUserNotFound", err)
+	}
+	if appErr := apperror.As(err); appErr == nil || appErr.Kind.Status() != http.StatusNotFound {
+		t.Fatalf("err = %v, want a 404 error", err)
+	}
+	if len(stats.read) != 0 {
+		t.Fatalf("stats were read for an unknown user: %v", stats.read)
+	}
+}
+
+func TestGetStatsActiveUser(t *testing.T) {
+	userID := uuid.New()
+	stats := &recordingStats{}
+	uc := NewRelationUseCase(nil, stats, &knownUsers{active: map[uuid.UUID]bool{userID: true}}, nil, nil)
+
+	got, err := uc.GetStats(context.Background(), userID)
+
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if got.UserID != userID {
+		t.Fatalf("UserID = %s, want %s", got.UserID, userID)
+	}
+}
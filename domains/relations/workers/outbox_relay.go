@@ -0,0 +1,107 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/malikhisyam/user-graph-service/domains/relations/usecases"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+	"go.uber.org/zap"
+)
+
+// outboxPruneInterval is how often published events past the retention are
+// deleted.
+const outboxPruneInterval = time.Hour
+
+// OutboxRelay publishes follow events from the outbox table, draining it
+// batch by batch on every tick, and prunes published events once they are
+// older than the retention.
+type OutboxRelay struct {
+	relationUc usecases.RelationUseCase
+	interval   time.Duration
+	batchSize  int
+	parkAfter  time.Duration
+	retention  time.Duration
+	logger     util.Logger
+}
+
+func NewOutboxRelay(
+	relationUc usecases.RelationUseCase,
+	interval time.Duration,
+	batchSize int,
+	parkAfter time.Duration,
+	retention time.Duration,
+	logger util.Logger,
+) *OutboxRelay {
+	return &OutboxRelay{
+		relationUc: relationUc,
+		interval:   interval,
+		batchSize:  batchSize,
+		parkAfter:  parkAfter,
+		retention:  retention,
+		logger:     logger,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (w *OutboxRelay) Run(ctx context.Context) {
+	if w.interval <= 0 || w.batchSize <= 0 {
+		w.logger.Warn("Outbox relay disabled, no interval or batch size configured")
+		return
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	// A nil channel never fires, which leaves pruning off.
+	var prune <-chan time.Time
+	if w.retention > 0 {
+		pruneTicker := time.NewTicker(outboxPruneInterval)
+		defer pruneTicker.Stop()
+		prune = pruneTicker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.drain(ctx)
+		case <-prune:
+			w.prune(ctx)
+		}
+	}
+}
+
+// drain stops at the first short batch, which means the outbox is empty, an
+// event failed, or another instance holds the relay lock.
+func (w *OutboxRelay) drain(ctx context.Context) {
+	total := 0
+	for ctx.Err() == nil {
+		published, err := w.relationUc.RelayOutbox(ctx, w.batchSize, w.parkAfter)
+		if err != nil {
+			w.logger.Error("Outbox relay failed", zap.Error(err))
+			break
+		}
+		total += published
+		if published < w.batchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		w.logger.Debug("Outbox events published", zap.Int("published", total))
+	}
+}
+
+func (w *OutboxRelay) prune(ctx context.Context) {
+	removed, err := w.relationUc.PruneOutbox(ctx, time.Now().Add(-w.retention))
+	if err != nil {
+		w.logger.Error("Outbox prune failed", zap.Error(err))
+		return
+	}
+
+	if removed > 0 {
+		w.logger.Info("Published outbox events pruned", zap.Int("removed", removed))
+	}
+}
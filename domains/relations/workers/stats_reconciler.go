@@ -0,0 +1,55 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/malikhisyam/user-graph-service/domains/relations/usecases"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+	"go.uber.org/zap"
+)
+
+// StatsReconciler periodically recomputes follower/following totals from the
+// follows table to repair any drift in the incrementally maintained counters.
+type StatsReconciler struct {
+	relationUc usecases.RelationUseCase
+	interval   time.Duration
+	logger     util.Logger
+}
+
+func NewStatsReconciler(relationUc usecases.RelationUseCase, interval time.Duration, logger util.Logger) *StatsReconciler {
+	return &StatsReconciler{
+		relationUc: relationUc,
+		interval:   interval,
+		logger:     logger,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (w *StatsReconciler) Run(ctx context.Context) {
+	if w.interval <= 0 {
+		w.logger.Warn("Stats reconciliation disabled, no interval configured")
+		return
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			started := time.Now()
+			repaired, err := w.relationUc.ReconcileStats(ctx)
+			if err != nil {
+				w.logger.Error("Stats reconciliation failed", zap.Error(err))
+				continue
+			}
+			w.logger.Info("Stats reconciliation finished",
+				zap.Int("repaired", repaired),
+				zap.Duration("took", time.Since(started)),
+			)
+		}
+	}
+}
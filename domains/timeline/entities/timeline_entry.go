@@ -0,0 +1,15 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimelineEntry is a post reference stored in Redis feeds. Posts themselves
+// live in the content service; the graph service only orders their IDs.
+type TimelineEntry struct {
+	PostID    string
+	AuthorID  uuid.UUID
+	Timestamp time.Time
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/timeline/models/requests"
+	"github.com/malikhisyam/user-graph-service/domains/timeline/models/responses"
+	"github.com/malikhisyam/user-graph-service/domains/timeline/usecases"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+)
+
+type TimelineHttp struct {
+	timelineUc usecases.TimelineUseCase
+}
+
+func NewTimelineHttp(timelineUc usecases.TimelineUseCase) *TimelineHttp {
+	return &TimelineHttp{
+		timelineUc: timelineUc,
+	}
+}
+
+func (h *TimelineHttp) PublishPost(c *gin.Context) {
+	var req requests.PostEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := util.AuthorizeActor(c.Request.Context(), req.AuthorID); err != nil {
+		c.JSON(util.AuthErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	fannedOut, err := h.timelineUc.PublishPost(c.Request.Context(), req.AuthorID, req.PostID, req.Timestamp)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "post event published", "fanned_out": fannedOut})
+}
+
+func (h *TimelineHttp) GetTimeline(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+
+	var after *util.Cursor
+	if rawCursor := c.Query("cursor"); rawCursor != "" {
+		after, err = util.DecodeCursor(rawCursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor parameter"})
+			return
+		}
+	}
+
+	entries, next, err := h.timelineUc.GetTimeline(c.Request.Context(), userID, limit, after)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	items := make([]responses.TimelineItemResponse, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, responses.TimelineItemResponse{
+			PostID:    e.PostID,
+			AuthorID:  e.AuthorID.String(),
+			Timestamp: e.Timestamp,
+		})
+	}
+
+	resp := responses.GetTimelineResponse{
+		Items: items,
+	}
+	if next != nil {
+		resp.NextCursor = util.EncodeCursor(*next)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,13 @@
+package requests
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PostEventRequest struct {
+	AuthorID  uuid.UUID `json:"author_id" binding:"required"`
+	PostID    string    `json:"post_id" binding:"required"`
+	Timestamp time.Time `json:"timestamp" binding:"required"`
+}
@@ -0,0 +1,14 @@
+package responses
+
+import "time"
+
+type TimelineItemResponse struct {
+	PostID    string    `json:"post_id"`
+	AuthorID  string    `json:"author_id"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type GetTimelineResponse struct {
+	Items      []TimelineItemResponse `json:"items"`
+	NextCursor string                 `json:"next_cursor,omitempty"`
+}
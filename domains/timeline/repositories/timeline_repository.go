@@ -0,0 +1,327 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/timeline/entities"
+	"github.com/malikhisyam/user-graph-service/infrastructures"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type TimelineRepository interface {
+	AddAuthorPost(ctx context.Context, entry entities.TimelineEntry, keep int) error
+	GetAuthorPosts(ctx context.Context, authorID uuid.UUID, limit int) ([]entities.TimelineEntry, error)
+	GetFollowerIDs(ctx context.Context, authorID uuid.UUID, after uuid.UUID, limit int) ([]uuid.UUID, error)
+	PushToFeeds(ctx context.Context, userIDs []uuid.UUID, entries []entities.TimelineEntry, keep int) error
+	RemoveFromFeed(ctx context.Context, userID uuid.UUID, entries []entities.TimelineEntry) error
+	RemoveFromFeeds(ctx context.Context, userIDs []uuid.UUID, entries []entities.TimelineEntry) error
+	DeleteUserFeeds(ctx context.Context, userID uuid.UUID) error
+	GetFeed(ctx context.Context, userID uuid.UUID, after *util.Cursor, limit int) ([]entities.TimelineEntry, error)
+	GetAuthorPostsAfter(ctx context.Context, authorID uuid.UUID, after *util.Cursor, limit int) ([]entities.TimelineEntry, error)
+	GetPulledAuthorIDs(ctx context.Context, userID uuid.UUID, threshold int64) ([]uuid.UUID, error)
+}
+
+type timelineRepository struct {
+	db         infrastructures.Database
+	redisCache *redis.Client
+	logger     util.Logger
+}
+
+func NewTimelineRepository(db infrastructures.Database, redisClient *redis.Client, logger util.Logger) TimelineRepository {
+	return &timelineRepository{
+		db:         db,
+		redisCache: redisClient,
+		logger:     logger,
+	}
+}
+
+func homeFeedKey(userID uuid.UUID) string {
+	return fmt.Sprintf("timeline:home:%s", userID.String())
+}
+
+func authorPostsKey(authorID uuid.UUID) string {
+	return fmt.Sprintf("timeline:author:%s", authorID.String())
+}
+
+// Feed members are "<author_id>:<post_id>" scored by the post time in unix
+// milliseconds, so a feed can be pruned per author without a second index.
+func entryMember(entry entities.TimelineEntry) string {
+	return entry.AuthorID.String() + ":" + entry.PostID
+}
+
+func entryScore(entry entities.TimelineEntry) float64 {
+	return float64(entry.Timestamp.UnixMilli())
+}
+
+func decodeEntry(z redis.Z) (entities.TimelineEntry, bool) {
+	member, ok := z.Member.(string)
+	if !ok {
+		return entities.TimelineEntry{}, false
+	}
+
+	authorRaw, postID, found := strings.Cut(member, ":")
+	if !found {
+		return entities.TimelineEntry{}, false
+	}
+
+	authorID, err := uuid.Parse(authorRaw)
+	if err != nil {
+		return entities.TimelineEntry{}, false
+	}
+
+	return entities.TimelineEntry{
+		PostID:    postID,
+		AuthorID:  authorID,
+		Timestamp: time.UnixMilli(int64(z.Score)).UTC(),
+	}, true
+}
+
+func (r *timelineRepository) AddAuthorPost(ctx context.Context, entry entities.TimelineEntry, keep int) error {
+	key := authorPostsKey(entry.AuthorID)
+
+	pipe := r.redisCache.TxPipeline()
+	pipe.ZAdd(ctx, key, redis.Z{Score: entryScore(entry), Member: entryMember(entry)})
+	pipe.ZRemRangeByRank(ctx, key, 0, int64(-keep-1))
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("Failed to store author post in Redis",
+			zap.Error(err),
+			zap.String("cache_key", key),
+			zap.String("post_id", entry.PostID),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (r *timelineRepository) GetAuthorPosts(ctx context.Context, authorID uuid.UUID, limit int) ([]entities.TimelineEntry, error) {
+	key := authorPostsKey(authorID)
+
+	zs, err := r.redisCache.ZRevRangeWithScores(ctx, key, 0, int64(limit-1)).Result()
+	if err != nil {
+		r.logger.Error("Failed to read author posts from Redis",
+			zap.Error(err),
+			zap.String("cache_key", key),
+		)
+		return nil, err
+	}
+
+	entries := make([]entities.TimelineEntry, 0, len(zs))
+	for _, z := range zs {
+		if entry, ok := decodeEntry(z); ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// GetFollowerIDs walks the author's followers in follower_id order, starting
+// after the given ID, so fan-out can proceed in bounded batches.
+func (r *timelineRepository) GetFollowerIDs(ctx context.Context, authorID uuid.UUID, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	var followerIDs []uuid.UUID
+
+	err := r.db.GetInstance().WithContext(ctx).
+		Table("follows").
+		Where("following_id = ? AND deleted_at IS NULL AND follower_id > ?", authorID, after).
+		Order("follower_id").
+		Limit(limit).
+		Pluck("follower_id", &followerIDs).Error
+	if err != nil {
+		r.logger.Error("Failed to load follower batch for fan-out",
+			zap.Error(err),
+			zap.String("author_id", authorID.String()),
+		)
+		return nil, err
+	}
+
+	return followerIDs, nil
+}
+
+func (r *timelineRepository) PushToFeeds(ctx context.Context, userIDs []uuid.UUID, entries []entities.TimelineEntry, keep int) error {
+	if len(userIDs) == 0 || len(entries) == 0 {
+		return nil
+	}
+
+	members := make([]redis.Z, 0, len(entries))
+	for _, entry := range entries {
+		members = append(members, redis.Z{Score: entryScore(entry), Member: entryMember(entry)})
+	}
+
+	pipe := r.redisCache.Pipeline()
+	for _, userID := range userIDs {
+		key := homeFeedKey(userID)
+		pipe.ZAdd(ctx, key, members...)
+		pipe.ZRemRangeByRank(ctx, key, 0, int64(-keep-1))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("Failed to push entries to home feeds",
+			zap.Error(err),
+			zap.Int("feeds", len(userIDs)),
+			zap.Int("entries", len(entries)),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (r *timelineRepository) RemoveFromFeed(ctx context.Context, userID uuid.UUID, entries []entities.TimelineEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	members := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		members = append(members, entryMember(entry))
+	}
+
+	key := homeFeedKey(userID)
+	if err := r.redisCache.ZRem(ctx, key, members...).Err(); err != nil {
+		r.logger.Error("Failed to prune entries from home feed",
+			zap.Error(err),
+			zap.String("cache_key", key),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (r *timelineRepository) RemoveFromFeeds(ctx context.Context, userIDs []uuid.UUID, entries []entities.TimelineEntry) error {
+	if len(userIDs) == 0 || len(entries) == 0 {
+		return nil
+	}
+
+	members := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		members = append(members, entryMember(entry))
+	}
+
+	pipe := r.redisCache.Pipeline()
+	for _, userID := range userIDs {
+		pipe.ZRem(ctx, homeFeedKey(userID), members...)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("Failed to prune entries from home feeds",
+			zap.Error(err),
+			zap.Int("feeds", len(userIDs)),
+			zap.Int("entries", len(entries)),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteUserFeeds drops the user's own home feed and recent posts.
+func (r *timelineRepository) DeleteUserFeeds(ctx context.Context, userID uuid.UUID) error {
+	if err := r.redisCache.Del(ctx, homeFeedKey(userID), authorPostsKey(userID)).Err(); err != nil {
+		r.logger.Error("Failed to delete timeline keys of user",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (r *timelineRepository) GetFeed(ctx context.Context, userID uuid.UUID, after *util.Cursor, limit int) ([]entities.TimelineEntry, error) {
+	return r.rangeAfter(ctx, homeFeedKey(userID), after, limit)
+}
+
+func (r *timelineRepository) GetAuthorPostsAfter(ctx context.Context, authorID uuid.UUID, after *util.Cursor, limit int) ([]entities.TimelineEntry, error) {
+	return r.rangeAfter(ctx, authorPostsKey(authorID), after, limit)
+}
+
+// GetPulledAuthorIDs lists the accounts userID follows whose follower total,
+// as maintained in user_stats from the follows table, reaches threshold.
+// Their posts are merged into the feed at read time instead of pushed.
+func (r *timelineRepository) GetPulledAuthorIDs(ctx context.Context, userID uuid.UUID, threshold int64) ([]uuid.UUID, error) {
+	var authorIDs []uuid.UUID
+
+	err := r.db.GetInstance().WithContext(ctx).
+		Table("follows AS f").
+		Joins("JOIN user_stats s ON s.user_id = f.following_id").
+		Where("f.follower_id = ? AND f.deleted_at IS NULL", userID).
+		Where("s.followers_count >= ?", threshold).
+		Pluck("f.following_id", &authorIDs).Error
+	if err != nil {
+		r.logger.Error("Failed to load pulled authors for feed",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+		)
+		return nil, err
+	}
+
+	return authorIDs, nil
+}
+
+// rangeAfter returns up to limit entries of a sorted set that come strictly
+// after the cursor in (score DESC, member DESC) order, which is the order
+// ZREVRANGEBYSCORE uses for equal scores.
+func (r *timelineRepository) rangeAfter(ctx context.Context, key string, after *util.Cursor, limit int) ([]entities.TimelineEntry, error) {
+	entries := make([]entities.TimelineEntry, 0, limit)
+
+	if after == nil {
+		zs, err := r.redisCache.ZRevRangeWithScores(ctx, key, 0, int64(limit-1)).Result()
+		if err != nil {
+			r.logger.Error("Failed to read feed from Redis",
+				zap.Error(err),
+				zap.String("cache_key", key),
+			)
+			return nil, err
+		}
+		for _, z := range zs {
+			if entry, ok := decodeEntry(z); ok {
+				entries = append(entries, entry)
+			}
+		}
+		return entries, nil
+	}
+
+	afterScore := after.CreatedAt.UnixMilli()
+	var offset int64
+	for len(entries) < limit {
+		zs, err := r.redisCache.ZRevRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+			Min:    "-inf",
+			Max:    strconv.FormatInt(afterScore, 10),
+			Offset: offset,
+			Count:  int64(limit),
+		}).Result()
+		if err != nil {
+			r.logger.Error("Failed to read feed page from Redis",
+				zap.Error(err),
+				zap.String("cache_key", key),
+			)
+			return nil, err
+		}
+		if len(zs) == 0 {
+			break
+		}
+		offset += int64(len(zs))
+
+		for _, z := range zs {
+			// Entries sharing the cursor's timestamp were already served up
+			// to and including the cursor member.
+			if int64(z.Score) == afterScore && z.Member.(string) >= after.ID {
+				continue
+			}
+			if entry, ok := decodeEntry(z); ok {
+				entries = append(entries, entry)
+			}
+			if len(entries) == limit {
+				break
+			}
+		}
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,260 @@
+package usecases
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	relationRepo "github.com/malikhisyam/user-graph-service/domains/relations/repositories"
+	"github.com/malikhisyam/user-graph-service/domains/timeline/entities"
+	"github.com/malikhisyam/user-graph-service/domains/timeline/repositories"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+	"go.uber.org/zap"
+)
+
+const (
+	// feedSize caps every home feed, older entries fall off the end.
+	feedSize = 800
+	// authorPostsSize is how many recent posts are kept per author for
+	// backfilling new followers and pruning unfollowed authors.
+	authorPostsSize = 200
+	// backfillSize is how many of an author's posts a new follower receives.
+	backfillSize = 50
+	// fanoutBatchSize bounds the follower IDs loaded per fan-out round trip.
+	fanoutBatchSize = 1000
+)
+
+type TimelineUseCase interface {
+	PublishPost(ctx context.Context, authorID uuid.UUID, postID string, timestamp time.Time) (int, error)
+	GetTimeline(ctx context.Context, userID uuid.UUID, limit int, after *util.Cursor) ([]entities.TimelineEntry, *util.Cursor, error)
+	OnFollow(ctx context.Context, followerID, authorID uuid.UUID)
+	OnUnfollow(ctx context.Context, followerID, authorID uuid.UUID)
+	PurgeUser(ctx context.Context, userID uuid.UUID) error
+}
+
+type timelineUsecase struct {
+	timelineRepo    repositories.TimelineRepository
+	statsRepo       relationRepo.StatsRepository
+	fanoutThreshold int64
+	logger          util.Logger
+}
+
+func NewTimelineUseCase(timelineRepo repositories.TimelineRepository, statsRepo relationRepo.StatsRepository, fanoutThreshold int64, logger util.Logger) TimelineUseCase {
+	return &timelineUsecase{
+		timelineRepo:    timelineRepo,
+		statsRepo:       statsRepo,
+		fanoutThreshold: fanoutThreshold,
+		logger:          logger,
+	}
+}
+
+// PublishPost records the post against its author and pushes it into the
+// home feed of the author and every follower, unless the author is above the
+// fan-out threshold, in which case readers pull it. It returns the number of
+// follower feeds written.
+func (u *timelineUsecase) PublishPost(ctx context.Context, authorID uuid.UUID, postID string, timestamp time.Time) (int, error) {
+	entry := entities.TimelineEntry{
+		PostID:    postID,
+		AuthorID:  authorID,
+		Timestamp: timestamp,
+	}
+	entries := []entities.TimelineEntry{entry}
+
+	if err := u.timelineRepo.AddAuthorPost(ctx, entry, authorPostsSize); err != nil {
+		return 0, err
+	}
+
+	if err := u.timelineRepo.PushToFeeds(ctx, []uuid.UUID{authorID}, entries, feedSize); err != nil {
+		return 0, err
+	}
+
+	if u.fanoutThreshold > 0 {
+		stats, err := u.statsRepo.GetStats(ctx, authorID)
+		if err != nil {
+			return 0, err
+		}
+		if stats.FollowersCount >= u.fanoutThreshold {
+			u.logger.Info("Skipped fan-out for high-follower author",
+				zap.String("author_id", authorID.String()),
+				zap.String("post_id", postID),
+				zap.Int64("followers", stats.FollowersCount),
+			)
+			return 0, nil
+		}
+	}
+
+	fannedOut := 0
+	after := uuid.Nil
+	for {
+		followerIDs, err := u.timelineRepo.GetFollowerIDs(ctx, authorID, after, fanoutBatchSize)
+		if err != nil {
+			return fannedOut, err
+		}
+		if len(followerIDs) == 0 {
+			break
+		}
+
+		if err := u.timelineRepo.PushToFeeds(ctx, followerIDs, entries, feedSize); err != nil {
+			return fannedOut, err
+		}
+		fannedOut += len(followerIDs)
+
+		if len(followerIDs) < fanoutBatchSize {
+			break
+		}
+		after = followerIDs[len(followerIDs)-1]
+	}
+
+	u.logger.Info("Post fanned out to followers",
+		zap.String("author_id", authorID.String()),
+		zap.String("post_id", postID),
+		zap.Int("feeds", fannedOut),
+	)
+	return fannedOut, nil
+}
+
+// GetTimeline returns one page of the user's home feed and the cursor of the
+// next page, which is nil once the feed is exhausted. Posts of pulled authors
+// are merged in here; every source is read past the same cursor and sorted
+// with the same (timestamp, member) order, so pages neither overlap nor skip.
+func (u *timelineUsecase) GetTimeline(ctx context.Context, userID uuid.UUID, limit int, after *util.Cursor) ([]entities.TimelineEntry, *util.Cursor, error) {
+	entries, err := u.timelineRepo.GetFeed(ctx, userID, after, limit+1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if u.fanoutThreshold > 0 {
+		authorIDs, err := u.timelineRepo.GetPulledAuthorIDs(ctx, userID, u.fanoutThreshold)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, authorID := range authorIDs {
+			pulled, err := u.timelineRepo.GetAuthorPostsAfter(ctx, authorID, after, limit+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			entries = append(entries, pulled...)
+		}
+
+		if len(authorIDs) > 0 {
+			entries = mergeEntries(entries)
+		}
+	}
+
+	if len(entries) <= limit {
+		return entries, nil, nil
+	}
+
+	entries = entries[:limit]
+	return entries, entryCursor(entries[len(entries)-1]), nil
+}
+
+// OnFollow backfills the new follower's feed with the author's recent posts.
+// Feed maintenance is best effort: failures are logged, never surfaced to
+// the follow itself.
+func (u *timelineUsecase) OnFollow(ctx context.Context, followerID, authorID uuid.UUID) {
+	recent, err := u.timelineRepo.GetAuthorPosts(ctx, authorID, backfillSize)
+	if err != nil {
+		return
+	}
+
+	if err := u.timelineRepo.PushToFeeds(ctx, []uuid.UUID{followerID}, recent, feedSize); err != nil {
+		return
+	}
+
+	u.logger.Debug("Backfilled home feed after follow",
+		zap.String("follower_id", followerID.String()),
+		zap.String("author_id", authorID.String()),
+		zap.Int("entries", len(recent)),
+	)
+}
+
+// OnUnfollow prunes the author's recent posts from the former follower's feed.
+func (u *timelineUsecase) OnUnfollow(ctx context.Context, followerID, authorID uuid.UUID) {
+	recent, err := u.timelineRepo.GetAuthorPosts(ctx, authorID, authorPostsSize)
+	if err != nil {
+		return
+	}
+
+	if err := u.timelineRepo.RemoveFromFeed(ctx, followerID, recent); err != nil {
+		return
+	}
+
+	u.logger.Debug("Pruned home feed after unfollow",
+		zap.String("follower_id", followerID.String()),
+		zap.String("author_id", authorID.String()),
+		zap.Int("entries", len(recent)),
+	)
+}
+
+// PurgeUser removes a deleted author's posts from their followers' feeds and
+// drops the user's own timeline keys. It must run before the follow edges
+// are purged, since it walks them to find the feeds to clean.
+func (u *timelineUsecase) PurgeUser(ctx context.Context, userID uuid.UUID) error {
+	recent, err := u.timelineRepo.GetAuthorPosts(ctx, userID, authorPostsSize)
+	if err != nil {
+		return err
+	}
+
+	if len(recent) > 0 {
+		after := uuid.Nil
+		for {
+			followerIDs, err := u.timelineRepo.GetFollowerIDs(ctx, userID, after, fanoutBatchSize)
+			if err != nil {
+				return err
+			}
+			if len(followerIDs) == 0 {
+				break
+			}
+
+			if err := u.timelineRepo.RemoveFromFeeds(ctx, followerIDs, recent); err != nil {
+				return err
+			}
+
+			if len(followerIDs) < fanoutBatchSize {
+				break
+			}
+			after = followerIDs[len(followerIDs)-1]
+		}
+	}
+
+	return u.timelineRepo.DeleteUserFeeds(ctx, userID)
+}
+
+func entryMember(entry entities.TimelineEntry) string {
+	return entry.AuthorID.String() + ":" + entry.PostID
+}
+
+func entryCursor(entry entities.TimelineEntry) *util.Cursor {
+	return &util.Cursor{
+		CreatedAt: entry.Timestamp,
+		ID:        entryMember(entry),
+	}
+}
+
+// mergeEntries sorts entries from several feeds newest first, breaking ties
+// on the member like Redis does, and drops posts present in more than one
+// source (an author pushed before crossing the threshold is also pulled).
+func mergeEntries(entries []entities.TimelineEntry) []entities.TimelineEntry {
+	sort.SliceStable(entries, func(i, j int) bool {
+		ti, tj := entries[i].Timestamp.UnixMilli(), entries[j].Timestamp.UnixMilli()
+		if ti != tj {
+			return ti > tj
+		}
+		return entryMember(entries[i]) > entryMember(entries[j])
+	})
+
+	merged := entries[:0]
+	seen := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		member := entryMember(entry)
+		if _, ok := seen[member]; ok {
+			continue
+		}
+		seen[member] = struct{}{}
+		merged = append(merged, entry)
+	}
+	return merged
+}
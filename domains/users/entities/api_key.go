@@ -0,0 +1,33 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApiKeys authenticate backend services. Only the SHA-256 of the key is
+// stored; Prefix is kept in clear so a key can be recognised in listings.
+type ApiKeys struct {
+	ID uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey;column:id"`
+
+	Name    string `gorm:"type:varchar(255);not null;column:name"`
+	Prefix  string `gorm:"type:varchar(16);not null;column:prefix"`
+	KeyHash string `gorm:"type:varchar(64);not null;uniqueIndex:idx_api_keys_key_hash;column:key_hash"`
+	// Scopes is a comma separated list, e.g. "relations:read,relations:write".
+	Scopes string `gorm:"type:varchar(255);not null;column:scopes"`
+
+	CreatedByID *uuid.UUID `gorm:"type:uuid;column:created_by_id"`
+	RevokedAt   *time.Time `gorm:"type:timestamp;column:revoked_at"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;column:created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp;column:updated_at"`
+}
+
+func (k *ApiKeys) ScopeList() []string {
+	if k.Scopes == "" {
+		return nil
+	}
+	return strings.Split(k.Scopes, ",")
+}
@@ -0,0 +1,26 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshTokens stores only the SHA-256 of each token. Every rotation creates
+// a new row in the same family and marks the previous one revoked and
+// replaced, so presenting a replaced token reveals reuse.
+type RefreshTokens struct {
+	ID uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey;column:id"`
+
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index:idx_refresh_tokens_user_id;column:user_id"`
+	FamilyID  uuid.UUID `gorm:"type:uuid;not null;index:idx_refresh_tokens_family_id;column:family_id"`
+	TokenHash string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_refresh_tokens_token_hash;column:token_hash"`
+
+	ReplacedByID *uuid.UUID `gorm:"type:uuid;column:replaced_by_id"`
+	ExpiresAt    time.Time  `gorm:"type:timestamp;not null;column:expires_at"`
+	RevokedAt    *time.Time `gorm:"type:timestamp;column:revoked_at"`
+
+	User User `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE;"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;column:created_at"`
+}
@@ -17,6 +17,13 @@ type User struct {
 	Phone     string         `gorm:"type:varchar(255)"`
 	Country   string         `gorm:"type:varchar(255)"`
 	Profile   string         `gorm:"type:varchar(255)"`
+	IsPrivate bool           `gorm:"type:boolean;not null;default:false"`
+	Role      string         `gorm:"type:varchar(32);not null;default:'user'"`
+	// DeactivatedAt hides the account from every listing; it can be undone
+	// until the reactivation window passes, after which it is purged.
+	DeactivatedAt *time.Time `gorm:"type:timestamp;index"`
+	// DeletionRequestedAt queues the account for the purge job right away.
+	DeletionRequestedAt *time.Time `gorm:"type:timestamp;index"`
 	CreatedAt time.Time      `gorm:"type:timestamp"`
 	UpdatedAt time.Time      `gorm:"type:timestamp"`
 }
\ No newline at end of file
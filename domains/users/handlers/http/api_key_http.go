@@ -0,0 +1,94 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/users/entities"
+	"github.com/malikhisyam/user-graph-service/domains/users/models/requests"
+	"github.com/malikhisyam/user-graph-service/domains/users/models/responses"
+	"github.com/malikhisyam/user-graph-service/domains/users/usecases"
+	"github.com/malikhisyam/user-graph-service/shared/apperror"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+)
+
+var errInvalidApiKeyID = apperror.Validation("invalid_api_key_id", "Invalid api key id")
+
+type ApiKeyHttp struct {
+	apiKeyUc usecases.ApiKeyUseCase
+}
+
+func NewApiKeyHttp(apiKeyUc usecases.ApiKeyUseCase) *ApiKeyHttp {
+	return &ApiKeyHttp{
+		apiKeyUc: apiKeyUc,
+	}
+}
+
+func (h *ApiKeyHttp) Create(c *gin.Context) {
+	var req requests.CreateApiKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Validation(apperror.CodeInvalidRequest, err.Error()))
+		return
+	}
+
+	// Keys minted by another key have no user to record as creator.
+	var createdBy *uuid.UUID
+	if authUser, err := util.GetAuthUser(c.Request.Context()); err == nil {
+		if userID, err := uuid.Parse(authUser.UserId); err == nil {
+			createdBy = &userID
+		}
+	}
+
+	rawKey, key, err := h.apiKeyUc.Create(c.Request.Context(), req.Name, req.Scopes, createdBy)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, responses.CreateApiKeyResponse{
+		ApiKeyResponse: toApiKeyResponse(key),
+		Key:            rawKey,
+	})
+}
+
+func (h *ApiKeyHttp) List(c *gin.Context) {
+	keys, err := h.apiKeyUc.List(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	res := make([]responses.ApiKeyResponse, len(keys))
+	for i := range keys {
+		res[i] = toApiKeyResponse(&keys[i])
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *ApiKeyHttp) Revoke(c *gin.Context) {
+	keyID, err := uuid.Parse(c.Param("keyId"))
+	if err != nil {
+		c.Error(errInvalidApiKeyID)
+		return
+	}
+
+	if err := h.apiKeyUc.Revoke(c.Request.Context(), keyID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "api key revoked"})
+}
+
+func toApiKeyResponse(key *entities.ApiKeys) responses.ApiKeyResponse {
+	return responses.ApiKeyResponse{
+		ID:        key.ID.String(),
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    key.ScopeList(),
+		RevokedAt: key.RevokedAt,
+		CreatedAt: key.CreatedAt,
+	}
+}
@@ -0,0 +1,351 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/users/entities"
+	"github.com/malikhisyam/user-graph-service/domains/users/models/requests"
+	"github.com/malikhisyam/user-graph-service/domains/users/models/responses"
+	"github.com/malikhisyam/user-graph-service/domains/users/repositories"
+	"github.com/malikhisyam/user-graph-service/domains/users/usecases"
+	"github.com/malikhisyam/user-graph-service/shared/apperror"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+)
+
+const (
+	maxSearchQueryLength = 100
+	maxSearchLimit       = 50
+)
+
+var (
+	errInvalidUserID      = apperror.Validation("invalid_user_id", "Invalid user id")
+	errInvalidTokenUser   = apperror.Unauthorized("unauthorized", "Invalid user in token")
+	errInvalidSearchQuery = apperror.Validation("invalid_query", "Query parameter q must be 1-100 characters")
+	errInvalidPage        = apperror.Validation("invalid_page", "Invalid page parameter")
+	errInvalidLimit       = apperror.Validation("invalid_limit", "Invalid limit parameter")
+)
+
+type UserHttp struct {
+	userUc usecases.UserUseCase
+}
+
+func NewUserHttp(userUc usecases.UserUseCase) *UserHttp {
+	return &UserHttp{
+		userUc: userUc,
+	}
+}
+
+func (h *UserHttp) Register(c *gin.Context) {
+	var req requests.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Validation(apperror.CodeInvalidRequest, err.Error()))
+		return
+	}
+
+	user, err := h.userUc.Register(c.Request.Context(), req.Name, req.Username, req.Email, req.Password)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toUserResponse(user))
+}
+
+func (h *UserHttp) Login(c *gin.Context) {
+	var req requests.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Validation(apperror.CodeInvalidRequest, err.Error()))
+		return
+	}
+
+	pair, user, err := h.userUc.Login(c.Request.Context(), req.Email, req.Password)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toLoginResponse(pair, user))
+}
+
+func (h *UserHttp) Refresh(c *gin.Context) {
+	var req requests.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Validation(apperror.CodeInvalidRequest, err.Error()))
+		return
+	}
+
+	pair, user, err := h.userUc.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toLoginResponse(pair, user))
+}
+
+func (h *UserHttp) Logout(c *gin.Context) {
+	authUser, err := util.GetAuthUser(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.userUc.Logout(c.Request.Context(), authUser); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "logout success"})
+}
+
+func (h *UserHttp) LogoutAll(c *gin.Context) {
+	authUser, err := util.GetAuthUser(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	userID, err := uuid.Parse(authUser.UserId)
+	if err != nil {
+		c.Error(errInvalidTokenUser)
+		return
+	}
+
+	if err := h.userUc.LogoutAll(c.Request.Context(), userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "all sessions revoked"})
+}
+
+func (h *UserHttp) Me(c *gin.Context) {
+	authUser, err := util.GetAuthUser(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	userID, err := uuid.Parse(authUser.UserId)
+	if err != nil {
+		c.Error(errInvalidTokenUser)
+		return
+	}
+
+	user, err := h.userUc.GetByID(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toUserResponse(user))
+}
+
+func (h *UserHttp) GetUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidUserID)
+		return
+	}
+
+	user, err := h.userUc.GetByID(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.respondWithProjection(c, user)
+}
+
+func (h *UserHttp) GetUserByUsername(c *gin.Context) {
+	user, err := h.userUc.GetByUsername(c.Request.Context(), c.Param("username"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.respondWithProjection(c, user)
+}
+
+// UpdateUser applies a partial profile update. Ownership is enforced by
+// middlewares.RequireOwner on the route.
+func (h *UserHttp) UpdateUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidUserID)
+		return
+	}
+
+	var req requests.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.Validation(apperror.CodeInvalidRequest, err.Error()))
+		return
+	}
+
+	user, err := h.userUc.UpdateProfile(c.Request.Context(), userID, usecases.ProfileUpdate{
+		Name:      req.Name,
+		Username:  req.Username,
+		Bio:       req.Bio,
+		Gender:    req.Gender,
+		Phone:     req.Phone,
+		Country:   req.Country,
+		Profile:   req.Profile,
+		IsPrivate: req.IsPrivate,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toUserResponse(user))
+}
+
+// Search looks users up by name or username for the search box.
+func (h *UserHttp) Search(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" || len(query) > maxSearchQueryLength {
+		c.Error(errInvalidSearchQuery)
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.Error(errInvalidPage)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > maxSearchLimit {
+		c.Error(errInvalidLimit)
+		return
+	}
+
+	// Callers without a user id, such as API keys, get the unpersonalised
+	// ranking.
+	callerID := uuid.Nil
+	if authUser, err := util.GetAuthUser(c.Request.Context()); err == nil {
+		if id, err := uuid.Parse(authUser.UserId); err == nil {
+			callerID = id
+		}
+	}
+
+	results, err := h.userUc.Search(c.Request.Context(), callerID, query, limit, (page-1)*limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	items := make([]responses.UserSearchItem, len(results))
+	for i, r := range results {
+		items[i] = responses.UserSearchItem{
+			ID:           r.ID,
+			Name:         r.Name,
+			Username:     r.Username,
+			AvatarURL:    r.AvatarURL,
+			IsPrivate:    r.IsPrivate,
+			FollowedByMe: r.FollowedByMe,
+			Score:        r.Similarity,
+		}
+	}
+
+	c.JSON(http.StatusOK, responses.SearchUsersResponse{
+		Users: items,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+func (h *UserHttp) Deactivate(c *gin.Context) {
+	h.changeAccountState(c, h.userUc.Deactivate, "account deactivated")
+}
+
+func (h *UserHttp) Reactivate(c *gin.Context) {
+	h.changeAccountState(c, h.userUc.Reactivate, "account reactivated")
+}
+
+// DeleteUser queues the account for permanent deletion; the purge job does
+// the actual work in the background.
+func (h *UserHttp) DeleteUser(c *gin.Context) {
+	h.changeAccountState(c, h.userUc.RequestDeletion, "account scheduled for deletion")
+}
+
+// changeAccountState runs one of the account lifecycle transitions on the
+// :id user. Ownership is enforced by middlewares.RequireOwner on the route.
+func (h *UserHttp) changeAccountState(c *gin.Context, transition func(ctx context.Context, userID uuid.UUID) error, message string) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidUserID)
+		return
+	}
+
+	if err := transition(c.Request.Context(), userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+// respondWithProjection returns the private projection to the user themself
+// and to admins, and the public one to everybody else. Deactivated accounts
+// are not found for anybody else.
+func (h *UserHttp) respondWithProjection(c *gin.Context, user *entities.User) {
+	authUser, err := util.GetAuthUser(c.Request.Context())
+	if err == nil && (authUser.UserId == user.ID.String() || authUser.IsAdmin()) {
+		c.JSON(http.StatusOK, toUserResponse(user))
+		return
+	}
+
+	if user.DeactivatedAt != nil {
+		c.Error(repositories.ErrUserNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, toPublicUserResponse(user))
+}
+
+func toLoginResponse(pair *usecases.TokenPair, user *entities.User) responses.LoginResponse {
+	return responses.LoginResponse{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    pair.ExpiresIn,
+		User:         toUserResponse(user),
+	}
+}
+
+func toUserResponse(user *entities.User) responses.UserResponse {
+	return responses.UserResponse{
+		ID:            user.ID.String(),
+		Name:          user.Name,
+		Username:      user.Username,
+		Email:         user.Email,
+		Bio:           user.Bio,
+		Gender:        user.Gender,
+		Phone:         user.Phone,
+		Country:       user.Country,
+		Profile:       user.Profile,
+		IsPrivate:     user.IsPrivate,
+		DeactivatedAt: user.DeactivatedAt,
+		CreatedAt:     user.CreatedAt,
+	}
+}
+
+func toPublicUserResponse(user *entities.User) responses.PublicUserResponse {
+	return responses.PublicUserResponse{
+		ID:        user.ID.String(),
+		Name:      user.Name,
+		Username:  user.Username,
+		Bio:       user.Bio,
+		Gender:    user.Gender,
+		Country:   user.Country,
+		Profile:   user.Profile,
+		IsPrivate: user.IsPrivate,
+		CreatedAt: user.CreatedAt,
+	}
+}
@@ -1,7 +1,58 @@
 package dto
 
+import "github.com/malikhisyam/user-graph-service/shared/constant"
+
 type AuthUserDto struct {
 	UserId string `json:"user_id"`
 	Name   string `json:"name"`
 	Email  string `json:"email"`
-}
\ No newline at end of file
+	Role   string `json:"role"`
+	// TokenId and SessionId are the jti and sid claims of the access token,
+	// empty for tokens minted without them.
+	TokenId   string `json:"-"`
+	SessionId string `json:"-"`
+	// ApiKeyId and Scopes are only set for callers authenticated with an
+	// API key; UserId is empty for them.
+	ApiKeyId string   `json:"api_key_id,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
+}
+
+// IsApiKey reports whether the caller is a service holding an API key rather
+// than a user holding a JWT.
+func (u *AuthUserDto) IsApiKey() bool {
+	return u.ApiKeyId != ""
+}
+
+// HasScope reports whether the caller may use routes guarded by scope. User
+// tokens are not scoped; what they can touch is decided by ownership checks.
+// The admin scope implies every other scope.
+func (u *AuthUserDto) HasScope(scope string) bool {
+	if !u.IsApiKey() {
+		return true
+	}
+
+	for _, s := range u.Scopes {
+		if s == scope || s == constant.SCOPE_ADMIN {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPrivileged reports whether the caller may act on behalf of other users:
+// tokens with the admin or service role, and API keys holding the admin
+// scope. Narrower keys are limited to what their scopes name.
+func (u *AuthUserDto) IsPrivileged() bool {
+	if u.IsApiKey() {
+		return u.HasScope(constant.SCOPE_ADMIN)
+	}
+	return u.Role == constant.ROLE_ADMIN || u.Role == constant.ROLE_SERVICE
+}
+
+// IsAdmin reports whether the caller may manage the service itself.
+func (u *AuthUserDto) IsAdmin() bool {
+	if u.IsApiKey() {
+		return u.HasScope(constant.SCOPE_ADMIN)
+	}
+	return u.Role == constant.ROLE_ADMIN
+}
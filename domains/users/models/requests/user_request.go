@@ -0,0 +1,35 @@
+package requests
+
+type RegisterRequest struct {
+	Name     string `json:"name" binding:"required,max=255"`
+	Username string `json:"username" binding:"required,max=255"`
+	Email    string `json:"email" binding:"required,email,max=255"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type CreateApiKeyRequest struct {
+	Name   string   `json:"name" binding:"required,max=255"`
+	Scopes []string `json:"scopes" binding:"required,min=1,dive,required"`
+}
+
+// UpdateProfileRequest is a partial update: omitted fields are left as they
+// are.
+type UpdateProfileRequest struct {
+	Name      *string `json:"name" binding:"omitempty,min=1,max=255"`
+	Username  *string `json:"username" binding:"omitempty,max=255"`
+	Bio       *string `json:"bio" binding:"omitempty,max=255"`
+	Gender    *string `json:"gender" binding:"omitempty,max=1"`
+	Phone     *string `json:"phone" binding:"omitempty,max=16"`
+	Country   *string `json:"country" binding:"omitempty,max=255"`
+	Profile   *string `json:"profile" binding:"omitempty,max=255"`
+	IsPrivate *bool   `json:"is_private"`
+}
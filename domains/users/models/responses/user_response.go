@@ -0,0 +1,84 @@
+package responses
+
+import "time"
+
+// UserResponse is the private projection, only returned to the user
+// themself and to admins.
+type UserResponse struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Username      string     `json:"username"`
+	Email         string     `json:"email"`
+	Bio           string     `json:"bio"`
+	Gender        string     `json:"gender"`
+	Phone         string     `json:"phone"`
+	Country       string     `json:"country"`
+	Profile       string     `json:"profile"`
+	IsPrivate     bool       `json:"is_private"`
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// PublicUserResponse is what other users see; it never carries the email or
+// phone number.
+type PublicUserResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Username  string    `json:"username"`
+	Bio       string    `json:"bio"`
+	Gender    string    `json:"gender"`
+	Country   string    `json:"country"`
+	Profile   string    `json:"profile"`
+	IsPrivate bool      `json:"is_private"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type LoginResponse struct {
+	AccessToken  string       `json:"access_token"`
+	RefreshToken string       `json:"refresh_token"`
+	TokenType    string       `json:"token_type"`
+	ExpiresIn    int64        `json:"expires_in"`
+	User         UserResponse `json:"user"`
+}
+
+type ApiKeyResponse struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Scopes    []string   `json:"scopes"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// CreateApiKeyResponse carries the plaintext key, returned only once.
+type CreateApiKeyResponse struct {
+	ApiKeyResponse
+	Key string `json:"key"`
+}
+
+type UserSearchResult struct {
+	ID            string
+	Name          string
+	Username      string
+	AvatarURL     string
+	IsPrivate     bool
+	ExactUsername bool
+	FollowedByMe  bool
+	Similarity    float64
+}
+
+type UserSearchItem struct {
+	ID           string  `json:"id"`
+	Name         string  `json:"name"`
+	Username     string  `json:"username"`
+	AvatarURL    string  `json:"avatar_url"`
+	IsPrivate    bool    `json:"is_private"`
+	FollowedByMe bool    `json:"followed_by_me"`
+	Score        float64 `json:"score"`
+}
+
+type SearchUsersResponse struct {
+	Users []UserSearchItem `json:"users"`
+	Page  int              `json:"page"`
+	Limit int              `json:"limit"`
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/users/entities"
+	"go.uber.org/zap"
+)
+
+// activeUserTTL bounds how long a cached answer may outlive a change made
+// outside this repository; changes made here drop the entry right away.
+const activeUserTTL = 10 * time.Minute
+
+func activeUserKey(userID uuid.UUID) string {
+	return fmt.Sprintf("user:active:%s", userID)
+}
+
+// AreActive reports whether every given user exists and is not deactivated.
+// Answers, negative ones included, are cached per user; only the misses go
+// to the database, in a single query.
+func (r *userRepository) AreActive(ctx context.Context, userIDs ...uuid.UUID) (bool, error) {
+	if len(userIDs) == 0 {
+		return true, nil
+	}
+
+	keys := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		keys = append(keys, activeUserKey(id))
+	}
+
+	var misses []uuid.UUID
+	cached, err := r.redisCache.MGet(ctx, keys...).Result()
+	if err != nil {
+		r.logger.Error("Redis error during AreActive",
+			zap.Error(err),
+			zap.Strings("cache_keys", keys),
+		)
+		misses = userIDs
+	} else {
+		for i, value := range cached {
+			switch value {
+			case "0":
+				return false, nil
+			case "1":
+			default:
+				misses = append(misses, userIDs[i])
+			}
+		}
+	}
+
+	if len(misses) == 0 {
+		return true, nil
+	}
+
+	var activeIDs []uuid.UUID
+	err = r.db.GetInstance().
+		WithContext(ctx).
+		Model(&entities.User{}).
+		Where("id IN ? AND deactivated_at IS NULL", misses).
+		Pluck("id", &activeIDs).Error
+	if err != nil {
+		r.logger.Error("Failed to check users are active", zap.Error(err))
+		return false, err
+	}
+
+	active := make(map[uuid.UUID]bool, len(activeIDs))
+	for _, id := range activeIDs {
+		active[id] = true
+	}
+
+	allActive := true
+	pipe := r.redisCache.Pipeline()
+	for _, id := range misses {
+		value := "1"
+		if !active[id] {
+			value = "0"
+			allActive = false
+		}
+		pipe.Set(ctx, activeUserKey(id), value, activeUserTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("Failed to cache active users", zap.Error(err))
+	}
+
+	return allActive, nil
+}
+
+// forgetActive drops the cached answer after a write that may change it.
+func (r *userRepository) forgetActive(ctx context.Context, userID uuid.UUID) {
+	cacheKey := activeUserKey(userID)
+	if err := r.redisCache.Del(ctx, cacheKey).Err(); err != nil {
+		r.logger.Error("Failed to invalidate active user in Redis cache",
+			zap.Error(err),
+			zap.String("cache_key", cacheKey),
+		)
+	}
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/users/entities"
+	"github.com/malikhisyam/user-graph-service/infrastructures"
+	"github.com/malikhisyam/user-graph-service/shared/apperror"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrApiKeyNotFound = apperror.NotFound("api_key_not_found", "api key not found")
+)
+
+type ApiKeyRepository interface {
+	Create(ctx context.Context, key *entities.ApiKeys) error
+	FindActiveByHash(ctx context.Context, keyHash string) (*entities.ApiKeys, error)
+	List(ctx context.Context) ([]entities.ApiKeys, error)
+	Revoke(ctx context.Context, keyID uuid.UUID) error
+}
+
+type apiKeyRepository struct {
+	db     infrastructures.Database
+	logger util.Logger
+}
+
+func NewApiKeyRepository(db infrastructures.Database, logger util.Logger) ApiKeyRepository {
+	return &apiKeyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *entities.ApiKeys) error {
+	if err := r.db.GetInstance().WithContext(ctx).Create(key).Error; err != nil {
+		r.logger.Error("Failed to create api key",
+			zap.Error(err),
+			zap.String("name", key.Name),
+		)
+		return err
+	}
+
+	r.logger.Info("Api key created",
+		zap.String("api_key_id", key.ID.String()),
+		zap.String("name", key.Name),
+		zap.String("scopes", key.Scopes),
+	)
+	return nil
+}
+
+func (r *apiKeyRepository) FindActiveByHash(ctx context.Context, keyHash string) (*entities.ApiKeys, error) {
+	var key entities.ApiKeys
+	err := r.db.GetInstance().
+		WithContext(ctx).
+		Where("key_hash = ? AND revoked_at IS NULL", keyHash).
+		First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApiKeyNotFound
+		}
+		r.logger.Error("Failed to load api key", zap.Error(err))
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (r *apiKeyRepository) List(ctx context.Context) ([]entities.ApiKeys, error) {
+	var keys []entities.ApiKeys
+	err := r.db.GetInstance().
+		WithContext(ctx).
+		Order("created_at DESC").
+		Find(&keys).Error
+	if err != nil {
+		r.logger.Error("Failed to list api keys", zap.Error(err))
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, keyID uuid.UUID) error {
+	result := r.db.GetInstance().
+		WithContext(ctx).
+		Model(&entities.ApiKeys{}).
+		Where("id = ? AND revoked_at IS NULL", keyID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		r.logger.Error("Failed to revoke api key",
+			zap.Error(result.Error),
+			zap.String("api_key_id", keyID.String()),
+		)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrApiKeyNotFound
+	}
+
+	r.logger.Info("Api key revoked", zap.String("api_key_id", keyID.String()))
+	return nil
+}
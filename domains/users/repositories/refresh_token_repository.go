@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/users/entities"
+	"github.com/malikhisyam/user-graph-service/infrastructures"
+	"github.com/malikhisyam/user-graph-service/shared/apperror"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRefreshTokenNotFound = apperror.Unauthorized("invalid_refresh_token", "refresh token not found")
+	ErrRefreshTokenReused   = apperror.Unauthorized("refresh_token_reused", "refresh token already used")
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *entities.RefreshTokens) error
+	FindByHash(ctx context.Context, tokenHash string) (*entities.RefreshTokens, error)
+	Rotate(ctx context.Context, current *entities.RefreshTokens, next *entities.RefreshTokens) error
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+}
+
+type refreshTokenRepository struct {
+	db     infrastructures.Database
+	logger util.Logger
+}
+
+func NewRefreshTokenRepository(db infrastructures.Database, logger util.Logger) RefreshTokenRepository {
+	return &refreshTokenRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *entities.RefreshTokens) error {
+	if err := r.db.GetInstance().WithContext(ctx).Create(token).Error; err != nil {
+		r.logger.Error("Failed to store refresh token",
+			zap.Error(err),
+			zap.String("user_id", token.UserID.String()),
+		)
+		return err
+	}
+	return nil
+}
+
+func (r *refreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*entities.RefreshTokens, error) {
+	var token entities.RefreshTokens
+	err := r.db.GetInstance().
+		WithContext(ctx).
+		Where("token_hash = ?", tokenHash).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRefreshTokenNotFound
+		}
+		r.logger.Error("Failed to load refresh token", zap.Error(err))
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Rotate retires current and stores next in one transaction. The update is
+// conditional on current still being live, so of two concurrent refreshes
+// with the same token only one wins and the other gets ErrRefreshTokenReused.
+func (r *refreshTokenRepository) Rotate(ctx context.Context, current *entities.RefreshTokens, next *entities.RefreshTokens) error {
+	err := r.db.GetInstance().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.RefreshTokens{}).
+			Where("id = ? AND revoked_at IS NULL", current.ID).
+			Updates(map[string]interface{}{
+				"revoked_at":     time.Now(),
+				"replaced_by_id": next.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRefreshTokenReused
+		}
+
+		return tx.Create(next).Error
+	})
+	if err != nil && !errors.Is(err, ErrRefreshTokenReused) {
+		r.logger.Error("Failed to rotate refresh token",
+			zap.Error(err),
+			zap.String("family_id", current.FamilyID.String()),
+		)
+	}
+	return err
+}
+
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	err := r.db.GetInstance().
+		WithContext(ctx).
+		Model(&entities.RefreshTokens{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		r.logger.Error("Failed to revoke refresh token family",
+			zap.Error(err),
+			zap.String("family_id", familyID.String()),
+		)
+		return err
+	}
+
+	r.logger.Info("Refresh token family revoked", zap.String("family_id", familyID.String()))
+	return nil
+}
+
+func (r *refreshTokenRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	err := r.db.GetInstance().
+		WithContext(ctx).
+		Model(&entities.RefreshTokens{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		r.logger.Error("Failed to revoke refresh tokens of user",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+		)
+		return err
+	}
+
+	r.logger.Info("All refresh tokens of user revoked", zap.String("user_id", userID.String()))
+	return nil
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/users/models/responses"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+	"go.uber.org/zap"
+)
+
+// SearchIndexStatements enable pg_trgm and index name and username for both
+// the substring matcher (util.NameMatch) and similarity ranking.
+var SearchIndexStatements = []string{
+	`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+	`CREATE INDEX IF NOT EXISTS idx_users_name_trgm ON users USING gin (name gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_users_username_trgm ON users USING gin (username gin_trgm_ops)`,
+}
+
+// Search matches query against name and username, either as a substring or
+// by trigram similarity for typos. Results rank an exact username first,
+// then accounts the caller already follows, then by similarity. Accounts
+// blocked in either direction and deactivated accounts are left out.
+func (r *userRepository) Search(ctx context.Context, callerID uuid.UUID, query string, limit, offset int) ([]responses.UserSearchResult, error) {
+	var results []responses.UserSearchResult
+
+	err := r.db.GetInstance().WithContext(ctx).Raw(`
+		SELECT
+			u.id,
+			u.name,
+			u.username,
+			u.profile AS avatar_url,
+			u.is_private,
+			LOWER(u.username) = LOWER(@query) AS exact_username,
+			EXISTS (
+				SELECT 1 FROM follows f
+				WHERE f.follower_id = @caller AND f.following_id = u.id AND f.deleted_at IS NULL
+			) AS followed_by_me,
+			GREATEST(similarity(u.username, @query), similarity(u.name, @query)) AS similarity
+		FROM users u
+		WHERE u.deactivated_at IS NULL
+			AND (
+				u.name ILIKE @pattern OR u.username ILIKE @pattern
+				OR u.name % @query OR u.username % @query
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM blocks b
+				WHERE (b.blocker_id = @caller AND b.blocked_id = u.id)
+					OR (b.blocker_id = u.id AND b.blocked_id = @caller)
+			)
+		ORDER BY exact_username DESC, followed_by_me DESC, similarity DESC, u.username
+		LIMIT @limit OFFSET @offset`,
+		map[string]interface{}{
+			"caller":  callerID,
+			"query":   query,
+			"pattern": util.ContainsPattern(query),
+			"limit":   limit,
+			"offset":  offset,
+		},
+	).Scan(&results).Error
+	if err != nil {
+		r.logger.Error("Failed to search users",
+			zap.Error(err),
+			zap.String("query", query),
+		)
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,187 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/users/entities"
+	"github.com/malikhisyam/user-graph-service/domains/users/models/responses"
+	"github.com/malikhisyam/user-graph-service/infrastructures"
+	"github.com/malikhisyam/user-graph-service/shared/apperror"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound      = apperror.NotFound("user_not_found", "user not found")
+	ErrDuplicateUsername = apperror.Conflict("username_taken", "username already exists")
+	ErrDuplicateEmail    = apperror.Conflict("email_taken", "email already exists")
+)
+
+type UserRepository interface {
+	Create(ctx context.Context, user *entities.User) error
+	FindByID(ctx context.Context, userID uuid.UUID) (*entities.User, error)
+	FindByEmail(ctx context.Context, email string) (*entities.User, error)
+	FindByUsername(ctx context.Context, username string) (*entities.User, error)
+	Update(ctx context.Context, userID uuid.UUID, fields map[string]interface{}) error
+	Search(ctx context.Context, callerID uuid.UUID, query string, limit, offset int) ([]responses.UserSearchResult, error)
+	FindPurgeable(ctx context.Context, deactivatedBefore time.Time, limit int) ([]uuid.UUID, error)
+	Delete(ctx context.Context, userID uuid.UUID) error
+	AreActive(ctx context.Context, userIDs ...uuid.UUID) (bool, error)
+}
+
+type userRepository struct {
+	db         infrastructures.Database
+	redisCache *redis.Client
+	logger     util.Logger
+}
+
+func NewUserRepository(db infrastructures.Database, redisClient *redis.Client, logger util.Logger) UserRepository {
+	return &userRepository{
+		db:         db,
+		redisCache: redisClient,
+		logger:     logger,
+	}
+}
+
+func (r *userRepository) Create(ctx context.Context, user *entities.User) error {
+	if err := r.db.GetInstance().WithContext(ctx).Create(user).Error; err != nil {
+		if dupErr := duplicateError(err); dupErr != nil {
+			return dupErr
+		}
+		r.logger.Error("Failed to create user in database",
+			zap.Error(err),
+			zap.String("username", user.Username),
+		)
+		return err
+	}
+	r.forgetActive(ctx, user.ID)
+
+	r.logger.Info("User created successfully",
+		zap.String("user_id", user.ID.String()),
+		zap.String("username", user.Username),
+	)
+	return nil
+}
+
+func (r *userRepository) FindByID(ctx context.Context, userID uuid.UUID) (*entities.User, error) {
+	return r.findOne(ctx, "id = ?", userID)
+}
+
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entities.User, error) {
+	return r.findOne(ctx, "LOWER(email) = LOWER(?)", email)
+}
+
+func (r *userRepository) FindByUsername(ctx context.Context, username string) (*entities.User, error) {
+	return r.findOne(ctx, "LOWER(username) = LOWER(?)", username)
+}
+
+// Update writes only the given columns; updated_at is always bumped.
+func (r *userRepository) Update(ctx context.Context, userID uuid.UUID, fields map[string]interface{}) error {
+	fields["updated_at"] = time.Now()
+
+	result := r.db.GetInstance().
+		WithContext(ctx).
+		Model(&entities.User{}).
+		Where("id = ?", userID).
+		Updates(fields)
+	if result.Error != nil {
+		if dupErr := duplicateError(result.Error); dupErr != nil {
+			return dupErr
+		}
+		r.logger.Error("Failed to update user",
+			zap.Error(result.Error),
+			zap.String("user_id", userID.String()),
+		)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	r.forgetActive(ctx, userID)
+
+	r.logger.Info("User updated successfully", zap.String("user_id", userID.String()))
+	return nil
+}
+
+// FindPurgeable lists accounts queued for deletion and accounts deactivated
+// before the given time, oldest first.
+func (r *userRepository) FindPurgeable(ctx context.Context, deactivatedBefore time.Time, limit int) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+
+	err := r.db.GetInstance().
+		WithContext(ctx).
+		Model(&entities.User{}).
+		Where("deletion_requested_at IS NOT NULL OR deactivated_at < ?", deactivatedBefore).
+		Order("COALESCE(deletion_requested_at, deactivated_at)").
+		Limit(limit).
+		Pluck("id", &userIDs).Error
+	if err != nil {
+		r.logger.Error("Failed to load accounts to purge", zap.Error(err))
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
+func (r *userRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	result := r.db.GetInstance().
+		WithContext(ctx).
+		Where("id = ?", userID).
+		Delete(&entities.User{})
+	if result.Error != nil {
+		r.logger.Error("Failed to delete user",
+			zap.Error(result.Error),
+			zap.String("user_id", userID.String()),
+		)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	r.forgetActive(ctx, userID)
+
+	r.logger.Info("User deleted", zap.String("user_id", userID.String()))
+	return nil
+}
+
+// duplicateError maps unique index violations on users to the sentinel for
+// the offending column, covering the race between the lookup and the write.
+func duplicateError(err error) error {
+	constraint, ok := util.UniqueViolation(err)
+	if !ok {
+		return nil
+	}
+
+	switch constraint {
+	case "idx_users_username":
+		return ErrDuplicateUsername
+	case "idx_users_email":
+		return ErrDuplicateEmail
+	}
+	return nil
+}
+
+func (r *userRepository) findOne(ctx context.Context, query string, arg interface{}) (*entities.User, error) {
+	var user entities.User
+	err := r.db.GetInstance().
+		WithContext(ctx).
+		Where(query, arg).
+		First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		r.logger.Error("Failed to load user",
+			zap.Error(err),
+			zap.String("query", query),
+		)
+		return nil, err
+	}
+
+	return &user, nil
+}
@@ -0,0 +1,85 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/shared/apperror"
+	"github.com/malikhisyam/user-graph-service/shared/events"
+)
+
+var (
+	ErrAlreadyDeactivated       = apperror.Conflict("already_deactivated", "account is already deactivated")
+	ErrNotDeactivated           = apperror.Conflict("not_deactivated", "account is not deactivated")
+	ErrReactivationWindowClosed = apperror.Conflict("reactivation_window_closed", "account can no longer be reactivated")
+)
+
+// Deactivate hides the account from every listing. The user keeps their
+// sessions so they can reactivate within the window.
+func (u *userUsecase) Deactivate(ctx context.Context, userID uuid.UUID) error {
+	user, err := u.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.DeactivatedAt != nil {
+		return ErrAlreadyDeactivated
+	}
+
+	if err := u.userRepo.Update(ctx, userID, map[string]interface{}{"deactivated_at": time.Now()}); err != nil {
+		return err
+	}
+
+	u.publish(ctx, events.New(events.UserDeactivated, map[string]interface{}{"user_id": userID.String()}))
+	return nil
+}
+
+func (u *userUsecase) Reactivate(ctx context.Context, userID uuid.UUID) error {
+	user, err := u.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.DeactivatedAt == nil {
+		return ErrNotDeactivated
+	}
+	if user.DeletionRequestedAt != nil || time.Since(*user.DeactivatedAt) > u.reactivationWindow {
+		return ErrReactivationWindowClosed
+	}
+
+	if err := u.userRepo.Update(ctx, userID, map[string]interface{}{"deactivated_at": nil}); err != nil {
+		return err
+	}
+
+	u.publish(ctx, events.New(events.UserReactivated, map[string]interface{}{"user_id": userID.String()}))
+	return nil
+}
+
+// RequestDeletion hides the account, ends every session and queues the
+// account for the purge job, which removes it for good.
+func (u *userUsecase) RequestDeletion(ctx context.Context, userID uuid.UUID) error {
+	user, err := u.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	fields := map[string]interface{}{"deletion_requested_at": now}
+	if user.DeactivatedAt == nil {
+		fields["deactivated_at"] = now
+	}
+	if err := u.userRepo.Update(ctx, userID, fields); err != nil {
+		return err
+	}
+
+	if err := u.LogoutAll(ctx, userID); err != nil {
+		return err
+	}
+
+	u.publish(ctx, events.New(events.UserDeletionRequested, map[string]interface{}{"user_id": userID.String()}))
+	return nil
+}
+
+// publish is best effort; the state change is already committed.
+func (u *userUsecase) publish(ctx context.Context, event events.Event) {
+	_ = u.publisher.Publish(ctx, event)
+}
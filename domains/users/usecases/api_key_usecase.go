@@ -0,0 +1,128 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/users/entities"
+	"github.com/malikhisyam/user-graph-service/domains/users/models/dto"
+	"github.com/malikhisyam/user-graph-service/domains/users/repositories"
+	"github.com/malikhisyam/user-graph-service/shared/apperror"
+	"github.com/malikhisyam/user-graph-service/shared/constant"
+)
+
+var (
+	ErrInvalidApiKey = apperror.Unauthorized("invalid_api_key", "invalid api key")
+	ErrUnknownScope  = apperror.Validation("unknown_scope", "unknown api key scope, expected relations:read, relations:write or admin")
+)
+
+const apiKeyPrefix = "ugs"
+
+type ApiKeyUseCase interface {
+	Create(ctx context.Context, name string, scopes []string, createdBy *uuid.UUID) (string, *entities.ApiKeys, error)
+	List(ctx context.Context) ([]entities.ApiKeys, error)
+	Revoke(ctx context.Context, keyID uuid.UUID) error
+	Authenticate(ctx context.Context, rawKey string) (*dto.AuthUserDto, error)
+}
+
+type apiKeyUsecase struct {
+	apiKeyRepo repositories.ApiKeyRepository
+}
+
+func NewApiKeyUseCase(apiKeyRepo repositories.ApiKeyRepository) ApiKeyUseCase {
+	return &apiKeyUsecase{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Create returns the plaintext key alongside the stored row. The plaintext
+// is never persisted, so this is the only time it can be shown.
+func (u *apiKeyUsecase) Create(ctx context.Context, name string, scopes []string, createdBy *uuid.UUID) (string, *entities.ApiKeys, error) {
+	for _, scope := range scopes {
+		if !isKnownScope(scope) {
+			return "", nil, fmt.Errorf("%w: %s", ErrUnknownScope, scope)
+		}
+	}
+
+	id := make([]byte, 4)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return "", nil, err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, err
+	}
+
+	prefix := fmt.Sprintf("%s_%s", apiKeyPrefix, hex.EncodeToString(id))
+	rawKey := fmt.Sprintf("%s_%s", prefix, base64.RawURLEncoding.EncodeToString(secret))
+
+	key := &entities.ApiKeys{
+		ID:          uuid.New(),
+		Name:        strings.TrimSpace(name),
+		Prefix:      prefix,
+		KeyHash:     hashApiKey(rawKey),
+		Scopes:      strings.Join(scopes, ","),
+		CreatedByID: createdBy,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if err := u.apiKeyRepo.Create(ctx, key); err != nil {
+		return "", nil, err
+	}
+
+	return rawKey, key, nil
+}
+
+func (u *apiKeyUsecase) List(ctx context.Context) ([]entities.ApiKeys, error) {
+	return u.apiKeyRepo.List(ctx)
+}
+
+func (u *apiKeyUsecase) Revoke(ctx context.Context, keyID uuid.UUID) error {
+	return u.apiKeyRepo.Revoke(ctx, keyID)
+}
+
+// Authenticate resolves a key to a service principal. Only keys with the
+// admin scope may act on behalf of users; see AuthUserDto.IsPrivileged.
+func (u *apiKeyUsecase) Authenticate(ctx context.Context, rawKey string) (*dto.AuthUserDto, error) {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix+"_") {
+		return nil, ErrInvalidApiKey
+	}
+
+	key, err := u.apiKeyRepo.FindActiveByHash(ctx, hashApiKey(rawKey))
+	if err != nil {
+		if errors.Is(err, repositories.ErrApiKeyNotFound) {
+			return nil, ErrInvalidApiKey
+		}
+		return nil, err
+	}
+
+	return &dto.AuthUserDto{
+		Name:     key.Name,
+		Role:     constant.ROLE_SERVICE,
+		ApiKeyId: key.ID.String(),
+		Scopes:   key.ScopeList(),
+	}, nil
+}
+
+func isKnownScope(scope string) bool {
+	for _, known := range constant.API_KEY_SCOPES {
+		if scope == known {
+			return true
+		}
+	}
+	return false
+}
+
+func hashApiKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,111 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/users/entities"
+	"github.com/malikhisyam/user-graph-service/domains/users/repositories"
+	"github.com/malikhisyam/user-graph-service/shared/apperror"
+	"github.com/malikhisyam/user-graph-service/shared/constant"
+)
+
+var (
+	ErrInvalidUsername = apperror.Validation("invalid_username", "username must be 3-30 characters of letters, digits, '_' or '.'")
+	ErrInvalidGender   = apperror.Validation("invalid_gender", "gender must be one of M, F or O")
+	ErrInvalidPhone    = apperror.Validation("invalid_phone", "phone must be in E.164 format, e.g. +6281234567890")
+	ErrNothingToUpdate = apperror.Validation("nothing_to_update", "no profile fields to update")
+)
+
+var (
+	usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_.]{3,30}$`)
+	phonePattern    = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+)
+
+// ProfileUpdate holds the fields of a partial profile update; nil fields
+// are left untouched. Gender and Phone may be set to "" to clear them.
+type ProfileUpdate struct {
+	Name      *string
+	Username  *string
+	Bio       *string
+	Gender    *string
+	Phone     *string
+	Country   *string
+	Profile   *string
+	IsPrivate *bool
+}
+
+func (u *userUsecase) GetByUsername(ctx context.Context, username string) (*entities.User, error) {
+	return u.userRepo.FindByUsername(ctx, strings.TrimSpace(username))
+}
+
+func (u *userUsecase) UpdateProfile(ctx context.Context, userID uuid.UUID, update ProfileUpdate) (*entities.User, error) {
+	fields := map[string]interface{}{}
+
+	if update.Name != nil {
+		fields["name"] = strings.TrimSpace(*update.Name)
+	}
+	if update.Username != nil {
+		username := strings.TrimSpace(*update.Username)
+		if err := validateUsername(username); err != nil {
+			return nil, err
+		}
+
+		existing, err := u.userRepo.FindByUsername(ctx, username)
+		if err == nil && existing.ID != userID {
+			return nil, ErrUsernameTaken
+		} else if err != nil && !errors.Is(err, repositories.ErrUserNotFound) {
+			return nil, err
+		}
+		fields["username"] = username
+	}
+	if update.Bio != nil {
+		fields["bio"] = strings.TrimSpace(*update.Bio)
+	}
+	if update.Gender != nil {
+		gender := strings.ToUpper(strings.TrimSpace(*update.Gender))
+		if gender != "" && gender != constant.GENDER_MALE && gender != constant.GENDER_FEMALE && gender != constant.GENDER_OTHER {
+			return nil, ErrInvalidGender
+		}
+		fields["gender"] = gender
+	}
+	if update.Phone != nil {
+		phone := strings.TrimSpace(*update.Phone)
+		if phone != "" && !phonePattern.MatchString(phone) {
+			return nil, ErrInvalidPhone
+		}
+		fields["phone"] = phone
+	}
+	if update.Country != nil {
+		fields["country"] = strings.TrimSpace(*update.Country)
+	}
+	if update.Profile != nil {
+		fields["profile"] = strings.TrimSpace(*update.Profile)
+	}
+	if update.IsPrivate != nil {
+		fields["is_private"] = *update.IsPrivate
+	}
+
+	if len(fields) == 0 {
+		return nil, ErrNothingToUpdate
+	}
+
+	if err := u.userRepo.Update(ctx, userID, fields); err != nil {
+		if errors.Is(err, repositories.ErrDuplicateUsername) {
+			return nil, ErrUsernameTaken
+		}
+		return nil, err
+	}
+
+	return u.userRepo.FindByID(ctx, userID)
+}
+
+func validateUsername(username string) error {
+	if !usernamePattern.MatchString(username) {
+		return ErrInvalidUsername
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/users/entities"
+	"github.com/malikhisyam/user-graph-service/domains/users/models/dto"
+	"github.com/malikhisyam/user-graph-service/domains/users/repositories"
+	"github.com/malikhisyam/user-graph-service/shared/apperror"
+)
+
+var (
+	ErrInvalidRefreshToken = apperror.Unauthorized("invalid_refresh_token", "invalid or expired refresh token")
+	ErrRefreshTokenReused  = apperror.Unauthorized("refresh_token_reused", "refresh token reuse detected, session revoked")
+)
+
+// Refresh exchanges a live refresh token for a new pair in the same family.
+// Presenting a token that was already rotated means it leaked, so the whole
+// family and every access token minted from it are revoked.
+func (u *userUsecase) Refresh(ctx context.Context, refreshToken string) (*TokenPair, *entities.User, error) {
+	if u.tokenSigner == nil {
+		return nil, nil, ErrTokenIssuance
+	}
+
+	current, err := u.refreshTokenRepo.FindByHash(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, repositories.ErrRefreshTokenNotFound) {
+			return nil, nil, ErrInvalidRefreshToken
+		}
+		return nil, nil, err
+	}
+
+	if current.RevokedAt != nil {
+		if current.ReplacedByID != nil {
+			return nil, nil, u.revokeReusedFamily(ctx, current.FamilyID)
+		}
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	if time.Now().After(current.ExpiresAt) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	user, err := u.userRepo.FindByID(ctx, current.UserID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return nil, nil, ErrInvalidRefreshToken
+		}
+		return nil, nil, err
+	}
+
+	nextToken, next, err := u.newRefreshToken(user.ID, current.FamilyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := u.refreshTokenRepo.Rotate(ctx, current, next); err != nil {
+		if errors.Is(err, repositories.ErrRefreshTokenReused) {
+			return nil, nil, u.revokeReusedFamily(ctx, current.FamilyID)
+		}
+		return nil, nil, err
+	}
+
+	pair, err := u.issueTokenPair(user, current.FamilyID, nextToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pair, user, nil
+}
+
+// Logout ends the caller's session: the presented access token, the refresh
+// token family it came from and any other access token of that family.
+func (u *userUsecase) Logout(ctx context.Context, authUser *dto.AuthUserDto) error {
+	if authUser.TokenId != "" {
+		if err := u.revocations.RevokeToken(ctx, authUser.TokenId); err != nil {
+			return err
+		}
+	}
+
+	if authUser.SessionId == "" {
+		return nil
+	}
+
+	familyID, err := uuid.Parse(authUser.SessionId)
+	if err != nil {
+		return nil
+	}
+
+	if err := u.refreshTokenRepo.RevokeFamily(ctx, familyID); err != nil {
+		return err
+	}
+	return u.revocations.RevokeSession(ctx, authUser.SessionId)
+}
+
+// LogoutAll ends every session of the user on every device.
+func (u *userUsecase) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	if err := u.refreshTokenRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+	return u.revocations.RevokeUser(ctx, userID.String())
+}
+
+func (u *userUsecase) revokeReusedFamily(ctx context.Context, familyID uuid.UUID) error {
+	if err := u.refreshTokenRepo.RevokeFamily(ctx, familyID); err != nil {
+		return err
+	}
+	if err := u.revocations.RevokeSession(ctx, familyID.String()); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// newRefreshToken returns the opaque token for the client and the row to
+// store, which only keeps its hash.
+func (u *userUsecase) newRefreshToken(userID, familyID uuid.UUID) (string, *entities.RefreshTokens, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	now := time.Now()
+	record := &entities.RefreshTokens{
+		ID:        uuid.New(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashRefreshToken(token),
+		ExpiresAt: now.Add(u.refreshTokenTTL),
+		CreatedAt: now,
+	}
+
+	return token, record, nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
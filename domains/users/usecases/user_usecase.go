@@ -0,0 +1,218 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/users/entities"
+	"github.com/malikhisyam/user-graph-service/domains/users/models/dto"
+	"github.com/malikhisyam/user-graph-service/domains/users/models/responses"
+	"github.com/malikhisyam/user-graph-service/domains/users/repositories"
+	"github.com/malikhisyam/user-graph-service/shared/apperror"
+	"github.com/malikhisyam/user-graph-service/shared/constant"
+	"github.com/malikhisyam/user-graph-service/shared/events"
+	"github.com/malikhisyam/user-graph-service/shared/security"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrEmailTaken         = apperror.Conflict("email_taken", "email already registered")
+	ErrUsernameTaken      = apperror.Conflict("username_taken", "username already taken")
+	ErrInvalidCredentials = apperror.Unauthorized("invalid_credentials", "invalid email or password")
+	ErrTokenIssuance      = apperror.New(apperror.KindInternal, "token_issuance_unavailable", "token issuance is not configured")
+)
+
+// TokenPair is what a successful login or refresh hands back to the client.
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int64
+}
+
+type UserUseCase interface {
+	Register(ctx context.Context, name, username, email, password string) (*entities.User, error)
+	Login(ctx context.Context, email, password string) (*TokenPair, *entities.User, error)
+	Refresh(ctx context.Context, refreshToken string) (*TokenPair, *entities.User, error)
+	Logout(ctx context.Context, authUser *dto.AuthUserDto) error
+	LogoutAll(ctx context.Context, userID uuid.UUID) error
+	GetByID(ctx context.Context, userID uuid.UUID) (*entities.User, error)
+	GetByUsername(ctx context.Context, username string) (*entities.User, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, update ProfileUpdate) (*entities.User, error)
+	Search(ctx context.Context, callerID uuid.UUID, query string, limit, offset int) ([]responses.UserSearchResult, error)
+	Deactivate(ctx context.Context, userID uuid.UUID) error
+	Reactivate(ctx context.Context, userID uuid.UUID) error
+	RequestDeletion(ctx context.Context, userID uuid.UUID) error
+}
+
+type userUsecase struct {
+	userRepo         repositories.UserRepository
+	refreshTokenRepo repositories.RefreshTokenRepository
+	revocations      *security.RevocationList
+	tokenSigner      *security.TokenSigner
+	accessTokenTTL   time.Duration
+	refreshTokenTTL  time.Duration
+	// reactivationWindow is how long a deactivated account can come back.
+	reactivationWindow time.Duration
+	publisher          events.Publisher
+}
+
+// NewUserUseCase wires the use case; tokenSigner may be nil when tokens are
+// issued by an external identity provider, in which case Login is disabled.
+func NewUserUseCase(
+	userRepo repositories.UserRepository,
+	refreshTokenRepo repositories.RefreshTokenRepository,
+	revocations *security.RevocationList,
+	tokenSigner *security.TokenSigner,
+	accessTokenTTL time.Duration,
+	refreshTokenTTL time.Duration,
+	reactivationWindow time.Duration,
+	publisher events.Publisher,
+) UserUseCase {
+	return &userUsecase{
+		userRepo:           userRepo,
+		refreshTokenRepo:   refreshTokenRepo,
+		revocations:        revocations,
+		tokenSigner:        tokenSigner,
+		accessTokenTTL:     accessTokenTTL,
+		refreshTokenTTL:    refreshTokenTTL,
+		reactivationWindow: reactivationWindow,
+		publisher:          publisher,
+	}
+}
+
+func (u *userUsecase) Register(ctx context.Context, name, username, email, password string) (*entities.User, error) {
+	email = strings.TrimSpace(email)
+	username = strings.TrimSpace(username)
+
+	if err := validateUsername(username); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.userRepo.FindByEmail(ctx, email); err == nil {
+		return nil, ErrEmailTaken
+	} else if !errors.Is(err, repositories.ErrUserNotFound) {
+		return nil, err
+	}
+
+	if _, err := u.userRepo.FindByUsername(ctx, username); err == nil {
+		return nil, ErrUsernameTaken
+	} else if !errors.Is(err, repositories.ErrUserNotFound) {
+		return nil, err
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &entities.User{
+		ID:        uuid.New(),
+		Name:      strings.TrimSpace(name),
+		Username:  username,
+		Email:     email,
+		Password:  string(hashed),
+		Role:      constant.ROLE_USER,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := u.userRepo.Create(ctx, user); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrDuplicateEmail):
+			return nil, ErrEmailTaken
+		case errors.Is(err, repositories.ErrDuplicateUsername):
+			return nil, ErrUsernameTaken
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Login checks the credentials and starts a new session: a short-lived
+// access token carrying the id, name and email claims AuthMiddleware reads,
+// plus the first refresh token of a new family.
+func (u *userUsecase) Login(ctx context.Context, email, password string) (*TokenPair, *entities.User, error) {
+	if u.tokenSigner == nil {
+		return nil, nil, ErrTokenIssuance
+	}
+
+	user, err := u.userRepo.FindByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return nil, nil, ErrInvalidCredentials
+		}
+		return nil, nil, err
+	}
+
+	if user.DeletionRequestedAt != nil {
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	refreshToken, record, err := u.newRefreshToken(user.ID, uuid.New())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := u.refreshTokenRepo.Create(ctx, record); err != nil {
+		return nil, nil, err
+	}
+
+	pair, err := u.issueTokenPair(user, record.FamilyID, refreshToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pair, user, nil
+}
+
+func (u *userUsecase) Search(ctx context.Context, callerID uuid.UUID, query string, limit, offset int) ([]responses.UserSearchResult, error) {
+	return u.userRepo.Search(ctx, callerID, strings.TrimSpace(query), limit, offset)
+}
+
+func (u *userUsecase) GetByID(ctx context.Context, userID uuid.UUID) (*entities.User, error) {
+	return u.userRepo.FindByID(ctx, userID)
+}
+
+func (u *userUsecase) issueTokenPair(user *entities.User, sessionID uuid.UUID, refreshToken string) (*TokenPair, error) {
+	accessToken, err := u.issueAccessToken(user, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(u.accessTokenTTL.Seconds()),
+	}, nil
+}
+
+// issueAccessToken stamps a unique jti so the token can be revoked on its
+// own, and the refresh token family as sid so a whole session can be.
+func (u *userUsecase) issueAccessToken(user *entities.User, sessionID uuid.UUID) (string, error) {
+	if u.tokenSigner == nil {
+		return "", ErrTokenIssuance
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"id":    user.ID.String(),
+		"name":  user.Name,
+		"email": user.Email,
+		"role":  user.Role,
+		"jti":   uuid.New().String(),
+		"sid":   sessionID.String(),
+		"iat":   now.Unix(),
+		"exp":   now.Add(u.accessTokenTTL).Unix(),
+	}
+
+	return u.tokenSigner.Sign(claims)
+}
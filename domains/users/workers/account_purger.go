@@ -0,0 +1,109 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/users/repositories"
+	"github.com/malikhisyam/user-graph-service/shared/events"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+	"go.uber.org/zap"
+)
+
+// purgeAccountsPerRun bounds how many accounts one tick works through.
+const purgeAccountsPerRun = 10
+
+// AccountCleaner removes what another domain keeps about a user. Cleaners
+// must be idempotent: a purge interrupted halfway is simply run again.
+type AccountCleaner interface {
+	PurgeUser(ctx context.Context, userID uuid.UUID) error
+}
+
+// AccountPurger permanently deletes accounts queued for deletion and
+// accounts left deactivated past the reactivation window. Cleaners run in
+// order before the user row goes, so later ones can still see the data
+// earlier ones depend on.
+type AccountPurger struct {
+	userRepo           repositories.UserRepository
+	cleaners           []AccountCleaner
+	publisher          events.Publisher
+	reactivationWindow time.Duration
+	interval           time.Duration
+	logger             util.Logger
+}
+
+func NewAccountPurger(
+	userRepo repositories.UserRepository,
+	cleaners []AccountCleaner,
+	publisher events.Publisher,
+	reactivationWindow time.Duration,
+	interval time.Duration,
+	logger util.Logger,
+) *AccountPurger {
+	return &AccountPurger{
+		userRepo:           userRepo,
+		cleaners:           cleaners,
+		publisher:          publisher,
+		reactivationWindow: reactivationWindow,
+		interval:           interval,
+		logger:             logger,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (w *AccountPurger) Run(ctx context.Context) {
+	if w.interval <= 0 {
+		w.logger.Warn("Account purge disabled, no interval configured")
+		return
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.runOnce(ctx)
+		}
+	}
+}
+
+func (w *AccountPurger) runOnce(ctx context.Context) {
+	userIDs, err := w.userRepo.FindPurgeable(ctx, time.Now().Add(-w.reactivationWindow), purgeAccountsPerRun)
+	if err != nil {
+		return
+	}
+
+	for _, userID := range userIDs {
+		started := time.Now()
+		if err := w.purge(ctx, userID); err != nil {
+			w.logger.Error("Account purge failed, will retry",
+				zap.Error(err),
+				zap.String("user_id", userID.String()),
+			)
+			continue
+		}
+		w.logger.Info("Account purged",
+			zap.String("user_id", userID.String()),
+			zap.Duration("took", time.Since(started)),
+		)
+	}
+}
+
+func (w *AccountPurger) purge(ctx context.Context, userID uuid.UUID) error {
+	for _, cleaner := range w.cleaners {
+		if err := cleaner.PurgeUser(ctx, userID); err != nil {
+			return err
+		}
+	}
+
+	if err := w.userRepo.Delete(ctx, userID); err != nil {
+		return err
+	}
+
+	_ = w.publisher.Publish(ctx, events.New(events.UserDeleted, map[string]interface{}{"user_id": userID.String()}))
+	return nil
+}
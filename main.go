@@ -1,14 +1,19 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	exports "github.com/malikhisyam/user-graph-service/domains/exports/entities"
 	relations "github.com/malikhisyam/user-graph-service/domains/relations/entities"
+	relationRepo "github.com/malikhisyam/user-graph-service/domains/relations/repositories"
 	users "github.com/malikhisyam/user-graph-service/domains/users/entities"
+	userRepo "github.com/malikhisyam/user-graph-service/domains/users/repositories"
 	"github.com/malikhisyam/user-graph-service/wizards"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -17,10 +22,42 @@ func main() {
 		log.Fatal("Error loading .env file")
 	}
 	fmt.Println("Loading environment successfully....")
-	wizards.PostgresDatabase.GetInstance().AutoMigrate(
+	err = wizards.PostgresDatabase.GetInstance().AutoMigrate(
 		&users.User{},
+		&users.RefreshTokens{},
+		&users.ApiKeys{},
 		&relations.Follows{},
+		&relations.Blocks{},
+		&relations.FollowRequests{},
+		&relations.UserStats{},
+		&relations.Outbox{},
+		&exports.DataExports{},
 	)
+	if err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+	err = wizards.PostgresDatabase.GetInstance().Transaction(func(tx *gorm.DB) error {
+		for _, statement := range relationRepo.ActivePairStatements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Failed to build the active follow index: %v", err)
+	}
+	for _, statement := range userRepo.SearchIndexStatements {
+		if err := wizards.PostgresDatabase.GetInstance().Exec(statement).Error; err != nil {
+			log.Printf("Failed to prepare user search index: %v", err)
+		}
+	}
+
+	go wizards.StatsReconciler.Run(context.Background())
+	go wizards.KeySet.Run(context.Background())
+	go wizards.AccountPurger.Run(context.Background())
+	go wizards.ExportWorker.Run(context.Background())
+	go wizards.OutboxRelay.Run(context.Background())
 
 	router := gin.Default()
 	wizards.RegisterServer(router)
@@ -0,0 +1,102 @@
+package apperror
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind decides the HTTP status an error is reported with.
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindUnprocessable
+)
+
+// Codes shared across domains; domain specific codes live next to their
+// sentinel errors.
+const (
+	CodeInternal       = "internal_error"
+	CodeInvalidRequest = "invalid_request"
+)
+
+// Error is a failure that is safe to show to clients: a stable, machine
+// readable Code and a human readable Message. Sentinels are compared by
+// identity, so errors.Is keeps working through wrapping.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func New(kind Kind, code, message string) *Error {
+	return &Error{
+		Kind:    kind,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func Validation(code, message string) *Error {
+	return New(KindValidation, code, message)
+}
+
+func Unauthorized(code, message string) *Error {
+	return New(KindUnauthorized, code, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return New(KindForbidden, code, message)
+}
+
+func NotFound(code, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return New(KindConflict, code, message)
+}
+
+// Unprocessable is for well formed requests that break a domain rule, such
+// as following yourself.
+func Unprocessable(code, message string) *Error {
+	return New(KindUnprocessable, code, message)
+}
+
+// As returns the first *Error in err's chain, or nil when there is none.
+func As(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return nil
+}
+
+// Status maps the kind to its HTTP status.
+func (k Kind) Status() int {
+	switch k {
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindUnprocessable:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1,5 +1,21 @@
 package constant
 
-var (
-	JWT_SECRET = []byte("HwGby8csscD0qnsXYF9WVv70OdHjpleAjVK5UGNadxgRc71anUqh1uWKDr5V3TgZ")
-)
\ No newline at end of file
+const (
+	ROLE_USER    = "user"
+	ROLE_ADMIN   = "admin"
+	ROLE_SERVICE = "service"
+)
+
+const (
+	SCOPE_RELATIONS_READ  = "relations:read"
+	SCOPE_RELATIONS_WRITE = "relations:write"
+	SCOPE_ADMIN           = "admin"
+)
+
+var API_KEY_SCOPES = []string{SCOPE_RELATIONS_READ, SCOPE_RELATIONS_WRITE, SCOPE_ADMIN}
+
+const (
+	GENDER_MALE   = "M"
+	GENDER_FEMALE = "F"
+	GENDER_OTHER  = "O"
+)
@@ -0,0 +1,66 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/malikhisyam/user-graph-service/shared/util"
+	"go.uber.org/zap"
+)
+
+const (
+	UserDeactivated       = "user.deactivated"
+	UserReactivated       = "user.reactivated"
+	UserDeletionRequested = "user.deletion_requested"
+	UserDeleted           = "user.deleted"
+
+	FollowCreated   = "follow.created"
+	FollowDeleted   = "follow.deleted"
+	FollowerRemoved = "follower.removed"
+)
+
+// Event is a domain event announced to other services. ID and Key are set
+// for events relayed from the outbox: ID lets consumers drop redeliveries
+// and events sharing a Key are delivered in order.
+type Event struct {
+	ID         string                 `json:"id,omitempty"`
+	Key        string                 `json:"key,omitempty"`
+	Type       string                 `json:"type"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Data       map[string]interface{} `json:"data"`
+}
+
+func New(eventType string, data map[string]interface{}) Event {
+	return Event{
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+// Publisher delivers events to whatever transport the deployment uses.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// LogPublisher writes events to the application log; it is the default when
+// no broker is configured.
+type LogPublisher struct {
+	logger util.Logger
+}
+
+func NewLogPublisher(logger util.Logger) *LogPublisher {
+	return &LogPublisher{
+		logger: logger,
+	}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, event Event) error {
+	p.logger.Info("Event published",
+		zap.String("id", event.ID),
+		zap.String("type", event.Type),
+		zap.Time("occurred_at", event.OccurredAt),
+		zap.Any("data", event.Data),
+	)
+	return nil
+}
@@ -0,0 +1,46 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStreamPublisher appends events to a Redis Stream. A single stream
+// keeps the order events were published in; it is trimmed to roughly maxLen
+// entries, zero meaning unbounded.
+type RedisStreamPublisher struct {
+	redisClient *redis.Client
+	stream      string
+	maxLen      int64
+}
+
+func NewRedisStreamPublisher(redisClient *redis.Client, stream string, maxLen int64) *RedisStreamPublisher {
+	return &RedisStreamPublisher{
+		redisClient: redisClient,
+		stream:      stream,
+		maxLen:      maxLen,
+	}
+}
+
+func (p *RedisStreamPublisher) Publish(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+
+	return p.redisClient.XAdd(ctx, &redis.XAddArgs{
+		Stream: p.stream,
+		MaxLen: p.maxLen,
+		Approx: p.maxLen > 0,
+		Values: map[string]interface{}{
+			"id":          event.ID,
+			"key":         event.Key,
+			"type":        event.Type,
+			"occurred_at": event.OccurredAt.Format(time.RFC3339Nano),
+			"data":        data,
+		},
+	}).Err()
+}
@@ -0,0 +1,37 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// WriterPublisher writes one JSON event per line, to stdout or a local file,
+// for development and for shipping events with a log collector.
+type WriterPublisher struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// NewFilePublisher appends to the file at path, creating it if needed.
+func NewFilePublisher(path string) (*WriterPublisher, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return NewWriterPublisher(file), nil
+}
+
+func (p *WriterPublisher) Publish(ctx context.Context, event Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.encoder.Encode(event)
+}
@@ -7,34 +7,71 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt"
 	"github.com/malikhisyam/user-graph-service/domains/users/models/dto"
 	"github.com/malikhisyam/user-graph-service/shared/constant"
 	"github.com/malikhisyam/user-graph-service/shared/models/responses"
+	"github.com/malikhisyam/user-graph-service/shared/security"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+const ApiKeyHeader = "X-API-Key"
+
+// ApiKeyAuthenticator resolves an API key to the service principal holding
+// it.
+type ApiKeyAuthenticator interface {
+	Authenticate(ctx context.Context, rawKey string) (*dto.AuthUserDto, error)
+}
+
+// AuthMiddleware accepts either an X-API-Key header or a bearer token. Bearer
+// tokens are rejected when they, their session or their user has been
+// revoked since they were issued.
+func AuthMiddleware(verifier *security.TokenVerifier, revocations *security.RevocationList, apiKeys ApiKeyAuthenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		accessToken := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
-
-		token, err := jwt.Parse(accessToken, func(token *jwt.Token) (interface{}, error) {
-			return constant.JWT_SECRET, nil
-		})
-		if err != nil || !token.Valid {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, responses.BasicResponse{Error: "Unauthorized"})
-			return
-		}
+		var authUser *dto.AuthUserDto
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, responses.BasicResponse{Error: "Invalid claims"})
-			return
-		}
+		if rawKey := c.GetHeader(ApiKeyHeader); rawKey != "" {
+			principal, err := apiKeys.Authenticate(c.Request.Context(), rawKey)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, responses.BasicResponse{Error: "Unauthorized"})
+				return
+			}
+			authUser = principal
+		} else {
+			accessToken := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+
+			claims, err := verifier.Verify(c.Request.Context(), accessToken)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, responses.BasicResponse{Error: "Unauthorized"})
+				return
+			}
+
+			userID := fmt.Sprintf("%v", claims["id"])
+			jti, _ := claims["jti"].(string)
+			sid, _ := claims["sid"].(string)
+			issuedAt, _ := claims["iat"].(float64)
+
+			revoked, err := revocations.IsRevoked(c.Request.Context(), jti, sid, userID, int64(issuedAt))
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, responses.BasicResponse{Error: "Unable to check token revocation"})
+				return
+			}
+			if revoked {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, responses.BasicResponse{Error: "Unauthorized"})
+				return
+			}
+
+			role, _ := claims["role"].(string)
+			if role == "" {
+				role = constant.ROLE_USER
+			}
 
-		authUser := &dto.AuthUserDto{
-			UserId: fmt.Sprintf("%v", claims["id"]),
-			Name:   fmt.Sprintf("%v", claims["name"]),
-			Email:  fmt.Sprintf("%v", claims["email"]),
+			authUser = &dto.AuthUserDto{
+				UserId:    userID,
+				Name:      fmt.Sprintf("%v", claims["name"]),
+				Email:     fmt.Sprintf("%v", claims["email"]),
+				Role:      role,
+				TokenId:   jti,
+				SessionId: sid,
+			}
 		}
 
 		ctx := context.WithValue(c.Request.Context(), "user", authUser)
@@ -42,4 +79,4 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		c.Next()
 	}
-}
\ No newline at end of file
+}
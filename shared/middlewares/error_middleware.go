@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/malikhisyam/user-graph-service/shared/apperror"
+	"github.com/malikhisyam/user-graph-service/shared/models/responses"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+	"go.uber.org/zap"
+)
+
+// ErrorHandler renders the error a handler attached with c.Error. Errors of
+// the apperror taxonomy keep their status, code and message; anything else
+// is logged and reported as a bare 500 so driver messages never reach
+// clients.
+func ErrorHandler(logger util.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		if appErr := apperror.As(err); appErr != nil && appErr.Kind != apperror.KindInternal {
+			c.AbortWithStatusJSON(appErr.Kind.Status(), responses.BasicResponse{Error: appErr.Message, Code: appErr.Code})
+			return
+		}
+
+		logger.Error("Request failed",
+			zap.Error(err),
+			zap.String("method", c.Request.Method),
+			zap.String("route", c.FullPath()),
+		)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, responses.BasicResponse{Error: "internal server error", Code: apperror.CodeInternal})
+	}
+}
@@ -0,0 +1,182 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/malikhisyam/user-graph-service/shared/models/responses"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// idempotencyLockTTL bounds how long a crashed request keeps its key
+	// locked; finished requests are kept for the configured TTL.
+	idempotencyLockTTL = time.Minute
+)
+
+// idempotencyRecord is what is stored under a key: the fingerprint of the
+// first request and, once it finished, its response.
+type idempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Done        bool   `json:"done"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// recordingWriter keeps a copy of the body written by the handler.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency makes writes carrying an Idempotency-Key header safe to retry.
+// The first request runs and its response is stored for ttl; a retry with
+// the same key and the same method, path and body gets the stored response
+// back, while reusing the key for a different request is rejected with 422.
+// Keys are scoped to the caller, so it must run after AuthMiddleware. Safe
+// methods and requests without the header pass through.
+//
+// Responses are stored in clear, so it must not guard routes that issue
+// credentials: a replayed login or refresh would hand the tokens out again.
+//
+// Only responses the handler wrote with a status below 500 are stored;
+// anything else releases the key so the retry runs again.
+func Idempotency(redisClient *redis.Client, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || isSafeMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, responses.BasicResponse{Error: "Idempotency-Key is too long", Code: "invalid_idempotency_key"})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, responses.BasicResponse{Error: "Unable to read request body", Code: "invalid_request"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		cacheKey := idempotencyKey(ctx, key)
+		fingerprint := requestFingerprint(c.Request, body)
+
+		pending, _ := json.Marshal(idempotencyRecord{Fingerprint: fingerprint})
+		acquired, err := redisClient.SetNX(ctx, cacheKey, pending, idempotencyLockTTL).Result()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, responses.BasicResponse{Error: "Unable to check idempotency key"})
+			return
+		}
+
+		if !acquired {
+			replayIdempotent(c, redisClient, cacheKey, fingerprint)
+			return
+		}
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// Errors left for ErrorHandler are rendered after this returns, so
+		// they are never recorded here.
+		if !writer.Written() || writer.Status() >= http.StatusInternalServerError {
+			redisClient.Del(context.Background(), cacheKey)
+			return
+		}
+
+		record, _ := json.Marshal(idempotencyRecord{
+			Fingerprint: fingerprint,
+			Done:        true,
+			Status:      writer.Status(),
+			ContentType: writer.Header().Get("Content-Type"),
+			Body:        writer.body.Bytes(),
+		})
+		redisClient.Set(context.Background(), cacheKey, record, ttl)
+	}
+}
+
+// replayIdempotent answers a request whose key is already taken.
+func replayIdempotent(c *gin.Context, redisClient *redis.Client, cacheKey, fingerprint string) {
+	raw, err := redisClient.Get(c.Request.Context(), cacheKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// The first request just released the key; let the client retry.
+		c.AbortWithStatusJSON(http.StatusConflict, responses.BasicResponse{Error: "A request with this Idempotency-Key is in progress", Code: "idempotency_key_in_progress"})
+		return
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, responses.BasicResponse{Error: "Unable to check idempotency key"})
+		return
+	}
+
+	var record idempotencyRecord
+	if err := json.Unmarshal(raw, &record); err != nil {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, responses.BasicResponse{Error: "Unable to check idempotency key"})
+		return
+	}
+
+	if record.Fingerprint != fingerprint {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, responses.BasicResponse{Error: "Idempotency-Key was already used for a different request", Code: "idempotency_key_reused"})
+		return
+	}
+	if !record.Done {
+		c.AbortWithStatusJSON(http.StatusConflict, responses.BasicResponse{Error: "A request with this Idempotency-Key is in progress", Code: "idempotency_key_in_progress"})
+		return
+	}
+
+	c.Header(IdempotencyReplayedHeader, "true")
+	c.Data(record.Status, record.ContentType, record.Body)
+	c.Abort()
+}
+
+// idempotencyKey scopes the client key to the caller so two callers can
+// never see each other's responses.
+func idempotencyKey(ctx context.Context, key string) string {
+	scope := "anonymous"
+	if user, err := util.GetAuthUser(ctx); err == nil {
+		if user.IsApiKey() {
+			scope = "key:" + user.ApiKeyId
+		} else {
+			scope = "user:" + user.UserId
+		}
+	}
+	return fmt.Sprintf("idempotency:%s:%s", scope, key)
+}
+
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/shared/models/responses"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+)
+
+// RequireOwner restricts a route to the user named by the given path
+// parameter, or to admins and admin-scoped API keys acting on their behalf.
+// It must run after AuthMiddleware.
+func RequireOwner(param string) gin.HandlerFunc {
+	return RequireScopedOwner(param, "")
+}
+
+// RequireScopedOwner is RequireOwner that also admits API keys holding
+// scope, for routes such keys may use on behalf of any user.
+func RequireScopedOwner(param, scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.Param(param))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, responses.BasicResponse{Error: "Invalid user id"})
+			return
+		}
+
+		if err := util.AuthorizeScopedActor(c.Request.Context(), userID, scope); err != nil {
+			c.AbortWithStatusJSON(util.AuthErrorStatus(err), responses.BasicResponse{Error: err.Error()})
+			return
+		}
+
+		c.Next()
+	}
+}
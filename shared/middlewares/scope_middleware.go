@@ -0,0 +1,63 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/malikhisyam/user-graph-service/shared/models/responses"
+	"github.com/malikhisyam/user-graph-service/shared/util"
+)
+
+// RequireScope restricts a route to API keys holding scope. User tokens pass
+// through. It must run after AuthMiddleware.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requireScope(c, scope)
+	}
+}
+
+// ScopeByMethod requires readScope for GET and HEAD requests and writeScope
+// for everything else, so a whole route group can be guarded at once.
+func ScopeByMethod(readScope, writeScope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope := writeScope
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			scope = readScope
+		}
+		requireScope(c, scope)
+	}
+}
+
+// RequireAdmin restricts a route to admin users and API keys with the admin
+// scope.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := util.GetAuthUser(c.Request.Context())
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, responses.BasicResponse{Error: err.Error()})
+			return
+		}
+
+		if !user.IsAdmin() {
+			c.AbortWithStatusJSON(http.StatusForbidden, responses.BasicResponse{Error: "Admin access required"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func requireScope(c *gin.Context, scope string) {
+	user, err := util.GetAuthUser(c.Request.Context())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, responses.BasicResponse{Error: err.Error()})
+		return
+	}
+
+	if !user.HasScope(scope) {
+		c.AbortWithStatusJSON(http.StatusForbidden, responses.BasicResponse{Error: "Missing scope " + scope})
+		return
+	}
+
+	c.Next()
+}
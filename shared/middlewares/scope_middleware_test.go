@@ -0,0 +1,82 @@
+package middlewares_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/malikhisyam/user-graph-service/domains/users/entities"
+	"github.com/malikhisyam/user-graph-service/domains/users/repositories"
+	"github.com/malikhisyam/user-graph-service/domains/users/usecases"
+	"github.com/malikhisyam/user-graph-service/shared/constant"
+	"github.com/malikhisyam/user-graph-service/shared/middlewares"
+)
+
+// memoryApiKeyRepository keeps keys in memory; the methods the test does not
+// need are left to the embedded interface.
+type memoryApiKeyRepository struct {
+	repositories.ApiKeyRepository
+	keys map[string]*entities.ApiKeys
+}
+
+func (r *memoryApiKeyRepository) Create(ctx context.Context, key *entities.ApiKeys) error {
+	r.keys[key.KeyHash] = key
+	return nil
+}
+
+func (r *memoryApiKeyRepository) FindActiveByHash(ctx context.Context, keyHash string) (*entities.ApiKeys, error) {
+	key, ok := r.keys[keyHash]
+	if !ok {
+		return nil, repositories.ErrApiKeyNotFound
+	}
+	return key, nil
+}
+
+func TestUserMutationRequiresAdminScopeForApiKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	apiKeys := usecases.NewApiKeyUseCase(&memoryApiKeyRepository{keys: map[string]*entities.ApiKeys{}})
+	readKey, _, err := apiKeys.Create(context.Background(), "reader", []string{constant.SCOPE_RELATIONS_READ}, nil)
+	if err != nil {
+		t.Fatalf("create read key: %v", err)
+	}
+	adminKey, _, err := apiKeys.Create(context.Background(), "admin", []string{constant.SCOPE_ADMIN}, nil)
+	if err != nil {
+		t.Fatalf("create admin key: %v", err)
+	}
+
+	router := gin.New()
+	router.PATCH("/users/:id",
+		middlewares.AuthMiddleware(nil, nil, apiKeys),
+		middlewares.RequireScope(constant.SCOPE_ADMIN),
+		middlewares.RequireOwner("id"),
+		func(c *gin.Context) { c.Status(http.StatusOK) },
+	)
+
+	tests := []struct {
+		name   string
+		key    string
+		status int
+	}{
+		{name: "read scoped key", key: readKey, status: http.StatusForbidden},
+		{name: "admin scoped key", key: adminKey, status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/users/"+uuid.NewString(), strings.NewReader(`{"name":"taken over"}`))
+			req.Header.Set(middlewares.ApiKeyHeader, tt.key)
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+		})
+	}
+}
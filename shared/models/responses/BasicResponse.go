@@ -1,6 +1,9 @@
 package responses
 
+// BasicResponse carries a payload or an error; Code is the stable, machine
+// readable counterpart of Error.
 type BasicResponse struct {
 	Data  interface{} `json:"data,omitempty"`
 	Error string      `json:"error,omitempty"`
-}
\ No newline at end of file
+	Code  string      `json:"code,omitempty"`
+}
@@ -0,0 +1,261 @@
+package security
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/malikhisyam/user-graph-service/shared/util"
+	"go.uber.org/zap"
+)
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// minRefreshGap throttles the on-demand reloads triggered by unknown kids so
+// a stream of forged tokens cannot hammer the JWKS endpoint.
+const minRefreshGap = 30 * time.Second
+
+// JWK is the subset of RFC 7517 fields needed for RSA and EC public keys.
+type JWK struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// KeySet holds the verification keys published in a JWKS document, loaded
+// from a local file or an http(s) URL and refreshed periodically so keys can
+// be rotated without a redeploy.
+type KeySet struct {
+	source          string
+	refreshInterval time.Duration
+	httpClient      *http.Client
+	logger          util.Logger
+
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	lastRefresh time.Time
+}
+
+func NewKeySet(source string, refreshInterval time.Duration, logger util.Logger) (*KeySet, error) {
+	k := &KeySet{
+		source:          source,
+		refreshInterval: refreshInterval,
+		httpClient:      &http.Client{Timeout: 10 * time.Second},
+		logger:          logger,
+		keys:            map[string]crypto.PublicKey{},
+	}
+
+	if err := k.Refresh(context.Background()); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Run refreshes the key set until ctx is cancelled. A failed refresh keeps
+// serving the previously loaded keys.
+func (k *KeySet) Run(ctx context.Context) {
+	if k.refreshInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(k.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := k.Refresh(ctx); err != nil {
+				k.logger.Error("Failed to refresh JWKS", zap.Error(err), zap.String("source", k.source))
+			}
+		}
+	}
+}
+
+// Key returns the public key for kid, reloading the set once if the kid is
+// not known yet, e.g. right after a rotation.
+func (k *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	k.mu.RLock()
+	key, ok := k.keys[kid]
+	stale := time.Since(k.lastRefresh) > minRefreshGap
+	k.mu.RUnlock()
+
+	if ok {
+		return key, nil
+	}
+
+	if stale {
+		if err := k.Refresh(ctx); err != nil {
+			k.logger.Error("Failed to refresh JWKS for unknown kid", zap.Error(err), zap.String("kid", kid))
+		}
+
+		k.mu.RLock()
+		key, ok = k.keys[kid]
+		k.mu.RUnlock()
+		if ok {
+			return key, nil
+		}
+	}
+
+	return nil, ErrUnknownKey
+}
+
+func (k *KeySet) Refresh(ctx context.Context) error {
+	raw, err := k.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	var doc JWKS
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return fmt.Errorf("decode jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(doc.Keys))
+	for _, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.PublicKey()
+		if err != nil {
+			k.logger.Warn("Skipping unusable JWK", zap.Error(err), zap.String("kid", jwk.Kid))
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return errors.New("jwks contains no usable signing keys")
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.lastRefresh = time.Now()
+	k.mu.Unlock()
+
+	k.logger.Info("JWKS loaded", zap.String("source", k.source), zap.Int("keys", len(keys)))
+	return nil
+}
+
+func (k *KeySet) fetch(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(k.source, "http://") && !strings.HasPrefix(k.source, "https://") {
+		return os.ReadFile(k.source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := k.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// PublicKey converts the JWK into an *rsa.PublicKey or *ecdsa.PublicKey.
+func (j JWK) PublicKey() (crypto.PublicKey, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := decodeBigInt(j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(j.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch j.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", j.Crv)
+		}
+		x, err := decodeBigInt(j.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(j.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", j.Kty)
+}
+
+// NewJWK describes a public key as a JWK, used by the key generator.
+func NewJWK(kid string, key crypto.PublicKey) (JWK, error) {
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kid: kid,
+			Kty: "RSA",
+			Alg: "RS256",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		return JWK{
+			Kid: kid,
+			Kty: "EC",
+			Alg: "ES256",
+			Use: "sig",
+			Crv: pub.Curve.Params().Name,
+			X:   base64.RawURLEncoding.EncodeToString(pub.X.FillBytes(make([]byte, size))),
+			Y:   base64.RawURLEncoding.EncodeToString(pub.Y.FillBytes(make([]byte, size))),
+		}, nil
+	}
+
+	return JWK{}, fmt.Errorf("unsupported public key type %T", key)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("decode jwk field: %w", err)
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
@@ -0,0 +1,90 @@
+package security
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// TokenSigner issues tokens with the private half of one of the keys in the
+// JWKS, stamping its kid so verifiers can pick the matching public key.
+type TokenSigner struct {
+	key      crypto.PrivateKey
+	method   jwt.SigningMethod
+	keyID    string
+	issuer   string
+	audience string
+}
+
+func NewTokenSigner(keyFile, keyID, issuer, audience string) (*TokenSigner, error) {
+	raw, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ParsePrivateKey(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var method jwt.SigningMethod
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		method = jwt.SigningMethodRS256
+	case *ecdsa.PrivateKey:
+		if k.Curve.Params().Name != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %s, ES256 needs P-256", k.Curve.Params().Name)
+		}
+		method = jwt.SigningMethodES256
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	return &TokenSigner{
+		key:      key,
+		method:   method,
+		keyID:    keyID,
+		issuer:   issuer,
+		audience: audience,
+	}, nil
+}
+
+func (s *TokenSigner) Sign(claims jwt.MapClaims) (string, error) {
+	if s.issuer != "" {
+		claims["iss"] = s.issuer
+	}
+	if s.audience != "" {
+		claims["aud"] = s.audience
+	}
+
+	token := jwt.NewWithClaims(s.method, claims)
+	token.Header["kid"] = s.keyID
+	return token.SignedString(s.key)
+}
+
+// ParsePrivateKey reads a PEM encoded PKCS#8, PKCS#1 or SEC 1 private key.
+func ParsePrivateKey(raw []byte) (crypto.PrivateKey, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("no PEM block found in private key file")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	return nil, errors.New("unsupported private key encoding")
+}
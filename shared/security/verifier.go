@@ -0,0 +1,62 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// TokenVerifier validates access tokens signed with RS256 or ES256 against
+// the key set, selecting the key by the kid header, and enforces exp, iss
+// and aud.
+type TokenVerifier struct {
+	keys     *KeySet
+	issuer   string
+	audience string
+}
+
+func NewTokenVerifier(keys *KeySet, issuer, audience string) *TokenVerifier {
+	return &TokenVerifier{
+		keys:     keys,
+		issuer:   issuer,
+		audience: audience,
+	}
+}
+
+func (v *TokenVerifier) Verify(ctx context.Context, tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.Alg() {
+		case jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg():
+		default:
+			return nil, ErrInvalidToken
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, ErrInvalidToken
+		}
+
+		return v.keys.Key(ctx, kid)
+	})
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	now := time.Now().Unix()
+	if !claims.VerifyExpiresAt(now, true) ||
+		!claims.VerifyIssuer(v.issuer, v.issuer != "") ||
+		!claims.VerifyAudience(v.audience, v.audience != "") {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
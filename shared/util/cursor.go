@@ -0,0 +1,37 @@
+package util
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/malikhisyam/user-graph-service/shared/apperror"
+)
+
+var ErrInvalidCursor = apperror.Validation("invalid_cursor", "invalid cursor")
+
+// Cursor marks a position in a list ordered by (created_at DESC, id DESC).
+// Clients only ever see it in its encoded, opaque form.
+type Cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+}
+
+func EncodeCursor(c Cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(encoded string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == "" || c.CreatedAt.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+
+	return &c, nil
+}
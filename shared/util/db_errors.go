@@ -0,0 +1,19 @@
+package util
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const pgUniqueViolation = "23505"
+
+// UniqueViolation returns the name of the unique constraint err violated, or
+// false when err is not a Postgres unique violation.
+func UniqueViolation(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return pgErr.ConstraintName, true
+	}
+	return "", false
+}
@@ -0,0 +1,23 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ContainsPattern turns free text into an ILIKE substring pattern, escaping
+// the LIKE wildcards so user input is matched literally.
+func ContainsPattern(text string) string {
+	return "%" + likeEscaper.Replace(strings.TrimSpace(text)) + "%"
+}
+
+// NameMatch builds the one filter every user listing uses: a case-insensitive
+// substring match on name or username of the users table aliased as alias.
+// Both columns carry pg_trgm GIN indexes, so the match stays indexed.
+func NameMatch(alias, text string) (string, []interface{}) {
+	pattern := ContainsPattern(text)
+	clause := fmt.Sprintf("(%[1]s.name ILIKE ? OR %[1]s.username ILIKE ?)", alias)
+	return clause, []interface{}{pattern, pattern}
+}
@@ -1,12 +1,29 @@
 package wizards
 
 import (
+	"log"
+	"os"
+
 	"github.com/malikhisyam/user-graph-service/config"
+	"github.com/malikhisyam/user-graph-service/shared/events"
+	"github.com/malikhisyam/user-graph-service/shared/security"
 	"github.com/malikhisyam/user-graph-service/shared/util"
 
+	exportHttp "github.com/malikhisyam/user-graph-service/domains/exports/handlers/http"
+	exportRepo "github.com/malikhisyam/user-graph-service/domains/exports/repositories"
+	exportUc "github.com/malikhisyam/user-graph-service/domains/exports/usecases"
+	exportWorkers "github.com/malikhisyam/user-graph-service/domains/exports/workers"
 	relationHttp "github.com/malikhisyam/user-graph-service/domains/relations/handlers/http"
 	relationRepo "github.com/malikhisyam/user-graph-service/domains/relations/repositories"
 	relationUc "github.com/malikhisyam/user-graph-service/domains/relations/usecases"
+	relationWorkers "github.com/malikhisyam/user-graph-service/domains/relations/workers"
+	timelineHttp "github.com/malikhisyam/user-graph-service/domains/timeline/handlers/http"
+	timelineRepo "github.com/malikhisyam/user-graph-service/domains/timeline/repositories"
+	timelineUc "github.com/malikhisyam/user-graph-service/domains/timeline/usecases"
+	userHttp "github.com/malikhisyam/user-graph-service/domains/users/handlers/http"
+	userRepo "github.com/malikhisyam/user-graph-service/domains/users/repositories"
+	userUc "github.com/malikhisyam/user-graph-service/domains/users/usecases"
+	userWorkers "github.com/malikhisyam/user-graph-service/domains/users/workers"
 	"github.com/malikhisyam/user-graph-service/infrastructures"
 )
 
@@ -15,7 +32,80 @@ var (
 	PostgresDatabase   = infrastructures.NewPostgresDatabase(Config)
 	RedisClient        = infrastructures.InitRedis()
 	LoggerInstance, _ = util.NewLogger();
+	KeySet = newKeySet()
+	TokenVerifier = security.NewTokenVerifier(KeySet, Config.Auth.Issuer, Config.Auth.Audience)
+	TokenSigner = newTokenSigner()
+	RevocationList = security.NewRevocationList(RedisClient, Config.Auth.AccessTokenTTL)
+	EventPublisher = newEventPublisher()
+	UserRepository = userRepo.NewUserRepository(PostgresDatabase, RedisClient, LoggerInstance)
 	RelationRepository = relationRepo.NewRelationRepository(PostgresDatabase, RedisClient, LoggerInstance)
-	RelationUseCase = relationUc.NewRelationUseCase(RelationRepository)
+	StatsRepository = relationRepo.NewStatsRepository(PostgresDatabase, RedisClient, LoggerInstance)
+	TimelineRepository = timelineRepo.NewTimelineRepository(PostgresDatabase, RedisClient, LoggerInstance)
+	TimelineUseCase = timelineUc.NewTimelineUseCase(TimelineRepository, StatsRepository, Config.Timeline.FanoutThreshold, LoggerInstance)
+	TimelineHttp = timelineHttp.NewTimelineHttp(TimelineUseCase)
+	RelationUseCase = relationUc.NewRelationUseCase(RelationRepository, StatsRepository, UserRepository, TimelineUseCase, EventPublisher)
 	RelationHttp = relationHttp.NewRelationHttp(RelationUseCase)
-)
\ No newline at end of file
+	RefreshTokenRepository = userRepo.NewRefreshTokenRepository(PostgresDatabase, LoggerInstance)
+	UserUseCase = userUc.NewUserUseCase(UserRepository, RefreshTokenRepository, RevocationList, TokenSigner, Config.Auth.AccessTokenTTL, Config.Auth.RefreshTokenTTL, Config.Accounts.ReactivationWindow, EventPublisher)
+	UserHttp = userHttp.NewUserHttp(UserUseCase)
+	ApiKeyRepository = userRepo.NewApiKeyRepository(PostgresDatabase, LoggerInstance)
+	ApiKeyUseCase = userUc.NewApiKeyUseCase(ApiKeyRepository)
+	ApiKeyHttp = userHttp.NewApiKeyHttp(ApiKeyUseCase)
+	ExportRepository = exportRepo.NewExportRepository(PostgresDatabase, LoggerInstance)
+	ExportUseCase = exportUc.NewExportUseCase(ExportRepository, UserRepository, newURLSigner(), Config.Exports.StorageDir, Config.Exports.LinkTTL, Config.Exports.Retention, LoggerInstance)
+	ExportHttp = exportHttp.NewExportHttp(ExportUseCase)
+	ExportWorker = exportWorkers.NewExportWorker(ExportUseCase, Config.Exports.PollInterval, LoggerInstance)
+	// The timeline cleaner walks follow edges, so it runs before they are purged.
+	AccountPurger = userWorkers.NewAccountPurger(UserRepository, []userWorkers.AccountCleaner{TimelineUseCase, RelationUseCase, ExportUseCase}, EventPublisher, Config.Accounts.ReactivationWindow, Config.Accounts.PurgeInterval, LoggerInstance)
+	StatsReconciler = relationWorkers.NewStatsReconciler(RelationUseCase, Config.Stats.ReconcileInterval, LoggerInstance)
+	OutboxRelay = relationWorkers.NewOutboxRelay(RelationUseCase, Config.Events.RelayInterval, Config.Events.RelayBatchSize, Config.Events.RelayParkAfter, Config.Events.OutboxRetention, LoggerInstance)
+)
+
+func newKeySet() *security.KeySet {
+	keys, err := security.NewKeySet(Config.Auth.JwksSource, Config.Auth.JwksRefreshInterval, LoggerInstance)
+	if err != nil {
+		log.Fatalf("Failed to load JWKS from %s: %v", Config.Auth.JwksSource, err)
+	}
+	return keys
+}
+
+func newTokenSigner() *security.TokenSigner {
+	if Config.Auth.SigningKeyFile == "" {
+		return nil
+	}
+
+	signer, err := security.NewTokenSigner(Config.Auth.SigningKeyFile, Config.Auth.SigningKeyId, Config.Auth.Issuer, Config.Auth.Audience)
+	if err != nil {
+		log.Fatalf("Failed to load token signing key from %s: %v", Config.Auth.SigningKeyFile, err)
+	}
+	return signer
+}
+
+func newURLSigner() *security.URLSigner {
+	if Config.Exports.SigningSecret == "" {
+		log.Println("EXPORTS_SIGNING_SECRET is not set, export download links will not survive a restart")
+	}
+
+	signer, err := security.NewURLSigner(Config.Exports.SigningSecret)
+	if err != nil {
+		log.Fatalf("Failed to set up export link signing: %v", err)
+	}
+	return signer
+}
+
+func newEventPublisher() events.Publisher {
+	switch Config.Events.Publisher {
+	case "stdout":
+		return events.NewWriterPublisher(os.Stdout)
+	case "file":
+		publisher, err := events.NewFilePublisher(Config.Events.FilePath)
+		if err != nil {
+			log.Fatalf("Failed to open event file %s: %v", Config.Events.FilePath, err)
+		}
+		return publisher
+	case "redis":
+		return events.NewRedisStreamPublisher(RedisClient, Config.Events.Stream, Config.Events.StreamMaxLen)
+	default:
+		return events.NewLogPublisher(LoggerInstance)
+	}
+}
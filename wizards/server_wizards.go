@@ -2,24 +2,134 @@ package wizards
 
 import (
 	"github.com/gin-gonic/gin"
-	// "github.com/malikhisyam/user-graph-service/shared/middlewares"
+	"github.com/malikhisyam/user-graph-service/shared/constant"
+	"github.com/malikhisyam/user-graph-service/shared/middlewares"
 )
 
 func RegisterServer(router *gin.Engine) {
+	router.Use(middlewares.ErrorHandler(LoggerInstance))
+	// Write routes replay their response when retried with the same
+	// Idempotency-Key; groups add it after authentication where they have
+	// it, so keys are scoped to the caller.
+	idempotency := middlewares.Idempotency(RedisClient, Config.Idempotency.TTL)
+
 	api := router.Group("/api")
 	v1 := api.Group("/v1")
+	auth := v1.Group("/auth")
+	{
+		// Sign Up
+		auth.POST("/register", UserHttp.Register)
+		// Sign In And Get An Access Token
+		auth.POST("/login", UserHttp.Login)
+		// Exchange A Refresh Token For A New Token Pair
+		auth.POST("/refresh", UserHttp.Refresh)
+		// Revoke The Current Session
+		auth.POST("/logout", middlewares.AuthMiddleware(TokenVerifier, RevocationList, ApiKeyUseCase), UserHttp.Logout)
+		// Revoke Every Session Of The Signed In User
+		auth.POST("/logout-all", middlewares.AuthMiddleware(TokenVerifier, RevocationList, ApiKeyUseCase), UserHttp.LogoutAll)
+	}
+
+	user := v1.Group("/users")
+	{
+		user.Use(middlewares.AuthMiddleware(TokenVerifier, RevocationList, ApiKeyUseCase))
+		user.Use(idempotency)
+		// Get Profile Of The Signed In User
+		user.GET("/me", UserHttp.Me)
+		// Request An Archive Of All Personal Data Of The Signed In User
+		user.POST("/me/export", ExportHttp.RequestExport)
+		// Get Status And Download Link Of A Personal Data Export
+		user.GET("/me/exports/:exportId", ExportHttp.GetExport)
+		// Search Users By Name Or Username
+		user.GET("/search", UserHttp.Search)
+		// Get Profile Of A User By Username
+		user.GET("/by-username/:username", UserHttp.GetUserByUsername)
+		// Get Profile Of A User
+		user.GET("/:id", UserHttp.GetUser)
+		// Update Profile Of A User
+		user.PATCH("/:id", middlewares.RequireScope(constant.SCOPE_ADMIN), middlewares.RequireOwner("id"), UserHttp.UpdateUser)
+		// Hide An Account Until It Is Reactivated
+		user.POST("/:id/deactivate", middlewares.RequireScope(constant.SCOPE_ADMIN), middlewares.RequireOwner("id"), UserHttp.Deactivate)
+		// Undo A Deactivation Within The Reactivation Window
+		user.POST("/:id/reactivate", middlewares.RequireScope(constant.SCOPE_ADMIN), middlewares.RequireOwner("id"), UserHttp.Reactivate)
+		// Permanently Delete An Account And Its Graph
+		user.DELETE("/:id", middlewares.RequireScope(constant.SCOPE_ADMIN), middlewares.RequireOwner("id"), UserHttp.DeleteUser)
+	}
+
+	export := v1.Group("/exports")
+	{
+		// Download A Personal Data Export Through Its Signed Link
+		export.GET("/:exportId/download", ExportHttp.Download)
+	}
+
 	relation := v1.Group("/relations")
 	{
-		// relation.Use(middlewares.AuthMiddleware())
+		relation.Use(middlewares.AuthMiddleware(TokenVerifier, RevocationList, ApiKeyUseCase))
+		relation.Use(middlewares.ScopeByMethod(constant.SCOPE_RELATIONS_READ, constant.SCOPE_RELATIONS_WRITE))
+		relation.Use(idempotency)
 		// Follow User 
 		relation.POST("/followings", RelationHttp.Follow)
+		// Follow Many Users At Once
+		relation.POST("/followings/bulk", RelationHttp.BulkFollow)
 		// Unfollow User
 		relation.DELETE("/followings", RelationHttp.Unfollow)
+		// Remove A Follower Without Blocking
+		relation.DELETE("/followers/:followerId", RelationHttp.RemoveFollower)
 		// See If A User Followed By A User
 		relation.GET("/:userId/followings/:targetUserId", RelationHttp.IsFollowing)
 		// Get Specific User His/Her Followers
 		relation.GET("/:userId/followers", RelationHttp.GetFollowers)
 		// Get Specific User His/Her Followings
 		relation.GET("/:userId/followings", RelationHttp.GetFollowings)
+		// Get Users Who Follow And Are Followed Back By A User
+		relation.GET("/:userId/mutuals", RelationHttp.GetMutuals)
+		// Get Accounts Followed By The People A User Follows
+		relation.GET("/:userId/suggestions", RelationHttp.GetSuggestions)
+		// Get Follower And Following Totals Of A User
+		relation.GET("/:userId/stats", RelationHttp.GetStats)
+		// Get Follow And Unfollow Events Of A User
+		relation.GET("/:userId/history", middlewares.RequireScopedOwner("userId", constant.SCOPE_RELATIONS_READ), RelationHttp.GetHistory)
+		// Block User
+		relation.POST("/blocks", RelationHttp.Block)
+		// Unblock User
+		relation.DELETE("/blocks", RelationHttp.Unblock)
+		// Get Follow Requests Sent To A Private Account
+		relation.GET("/:userId/requests/incoming", middlewares.RequireScopedOwner("userId", constant.SCOPE_RELATIONS_READ), RelationHttp.GetIncomingFollowRequests)
+		// Get Follow Requests Sent By A User
+		relation.GET("/:userId/requests/outgoing", middlewares.RequireScopedOwner("userId", constant.SCOPE_RELATIONS_READ), RelationHttp.GetOutgoingFollowRequests)
+		// Approve Follow Request
+		relation.POST("/requests/:requestId/approve", RelationHttp.ApproveFollowRequest)
+		// Reject Follow Request
+		relation.POST("/requests/:requestId/reject", RelationHttp.RejectFollowRequest)
+		// Cancel Follow Request
+		relation.DELETE("/requests/:requestId", RelationHttp.CancelFollowRequest)
+	}
+
+	timeline := v1.Group("/timeline")
+	{
+		timeline.Use(middlewares.AuthMiddleware(TokenVerifier, RevocationList, ApiKeyUseCase))
+		timeline.Use(middlewares.RequireScope(constant.SCOPE_ADMIN))
+		timeline.Use(idempotency)
+		// Publish A Post To The Author's Followers
+		timeline.POST("/events", TimelineHttp.PublishPost)
+		// Get Home Feed Of A User
+		timeline.GET("/:userId", middlewares.RequireOwner("userId"), TimelineHttp.GetTimeline)
+	}
+
+	admin := v1.Group("/admin")
+	{
+		admin.Use(middlewares.AuthMiddleware(TokenVerifier, RevocationList, ApiKeyUseCase))
+		admin.Use(middlewares.RequireAdmin())
+		// Create A Scoped Api Key For A Backend Service
+		admin.POST("/api-keys", ApiKeyHttp.Create)
+		// List Api Keys
+		admin.GET("/api-keys", ApiKeyHttp.List)
+		// Revoke Api Key
+		admin.DELETE("/api-keys/:keyId", idempotency, ApiKeyHttp.Revoke)
+		// List Follow Events The Outbox Relay Parked After Failing Too Long
+		admin.GET("/outbox/parked", RelationHttp.ListParkedEvents)
+		// Release Every Parked Follow Event Back To The Relay
+		admin.POST("/outbox/parked/replay", idempotency, RelationHttp.ReplayParkedEvents)
+		// Release One Parked Follow Event Back To The Relay
+		admin.POST("/outbox/parked/:eventId/replay", idempotency, RelationHttp.ReplayParkedEvent)
 	}
 }
\ No newline at end of file